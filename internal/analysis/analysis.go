@@ -1,9 +1,11 @@
 package analysis
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"sort"
 	"strings"
 
@@ -17,86 +19,214 @@ var (
 )
 
 type organisationChartAnalyser struct {
-	chart   model.OrganisationChart
-	output  io.Writer
-	adjList map[int][]int    // graph structure for BFS traversal
-	nameMap map[string][]int // used to look up names when building string from path ID's
+	chart       model.OrganisationChart
+	output      io.Writer
+	adjList     map[int][]int          // graph structure for BFS traversal
+	nameMap     map[string][]int       // used to look up names when building string from path ID's
+	employeeMap map[int]model.Employee // used to look up employees by ID when walking management links
+	reportsMap  map[int][]int          // directed manager > report index, unlike adjList which goes both ways
+
+	relationshipTypes []model.RelationshipType          // which kinds of relationship a path may traverse
+	relationshipMap   map[[2]int]model.RelationshipType // [employee, manager] > the type of line between them
+
+	normalisedNameMap map[string][]int // same as nameMap, but keyed by case/whitespace/diacritic-insensitive names
+	fuzzyAutoSelect   bool
+	strictNames       bool
+	shortestPathOnly  bool
+	allShortestPaths  bool
+	kShortestPaths    int
+	costRules         *CostRules // nil unless a weighted search was asked for
+	searchStrategy    SearchStrategy
+	avoiding          []string         // selectors for employees a path must not pass through
+	requiring         []string         // selectors for employees a path must pass through, in order
+	options           []AnalyserOption // kept so that analysers for derived charts (e.g. a reorg) behave the same way
+	tables            *Tables          // precomputed lookups for this chart, e.g. from an index - nil builds them from the chart
+	depths            map[int]int      // from the tables - nil unless tables were given
+	ancestors         map[int][]int    // from the tables - nil unless tables were given
+	ctx               context.Context  // nil unless the query was given one, see withContext
 }
 
 type OrganisationChartAnalysis struct{}
 
-func NewOrganisationChartAnalyser(output io.Writer, chart model.OrganisationChart) *organisationChartAnalyser {
+func NewOrganisationChartAnalyser(output io.Writer, chart model.OrganisationChart, opts ...AnalyserOption) *organisationChartAnalyser {
 	analyser := &organisationChartAnalyser{
-		chart:  chart,
-		output: output,
+		chart:             chart,
+		output:            output,
+		relationshipTypes: []model.RelationshipType{model.SolidLine},
+		options:           opts,
+	}
+
+	for _, opt := range opts {
+		opt(analyser)
+	}
+
+	analyser.employeeMap = analyser.mapEmployeesById()
+
+	if analyser.tables != nil {
+		analyser.useTables()
+		return analyser
 	}
 
 	analyser.adjList = analyser.mapEmployees()
+	analyser.relationshipMap = analyser.mapRelationships()
 	analyser.nameMap = analyser.mapEmployeeNames()
+	analyser.reportsMap = analyser.mapReports()
+	analyser.normalisedNameMap = analyser.mapNormalisedEmployeeNames()
 
 	return analyser
 }
 
+// Arrows point in the direction of management flow - employee to manager is "up".
+var upArrows = map[model.RelationshipType]string{
+	model.SolidLine:  "->",
+	model.DottedLine: "..>",
+	model.Interim:    "~>",
+}
+
+var downArrows = map[model.RelationshipType]string{
+	model.SolidLine:  "<-",
+	model.DottedLine: "<..",
+	model.Interim:    "<~",
+}
+
+// A candidate path between two employees, with the endpoints it was found between.
+// With duplicate names there can be several of these for a single query.
+type PathResult struct {
+	Ids    []int          `json:"ids"`
+	Start  model.Employee `json:"start"`
+	Target model.Employee `json:"target"`
+	Cost   float64        `json:"cost"` // the number of hops, or the total hop cost for a weighted search
+}
+
 // Breadth-first search to traverse graph.
 // If we wanted to make this code as optimal as possi
 func (a *organisationChartAnalyser) Analyse(name1 string, name2 string) error {
+	paths, err := a.Paths(name1, name2)
 
-	// Validate that the names actually exist
-	err := a.validateNames(name1, name2)
+	if err != nil {
+		return err
+	}
+
+	if a.shortestPathOnly {
+		paths = paths[:1]
+	}
+
+	output, err := a.pathsToString(paths)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = a.output.Write([]byte(output))
 
 	if err != nil {
 		return err
 	}
 
+	return nil
+}
+
+// Returns every candidate path between the employees matching the two names, ranked by length.
+func (a *organisationChartAnalyser) Paths(name1 string, name2 string) ([]PathResult, error) {
+
+	// Validate that the names actually exist
+	startIds, targetIds, err := a.validateNames(name1, name2)
+
+	if err != nil {
+		return nil, err
+	}
+
+	constraints, err := a.resolveConstraints()
+
+	if err != nil {
+		return nil, err
+	}
+
 	/*
 		There is no guarantee names are unique
 		As such, the only thing we can really do to calculate the shortest path
 		Is generate all possible paths for each instance of a duplicated name
-		And use the shortest one in our final output
+		And rank them, so the caller can see which of the employees each one was between
 	*/
 
-	startIds := a.nameMap[name1]
-	targetIds := a.nameMap[name2]
-
 	// Store all the paths so we can then sort them to find and return the shortest one
-	allPaths := make([][]int, 0)
+	allPaths := make([]PathResult, 0)
 
 	for _, startId := range startIds {
 		for _, targetId := range targetIds {
-			pathIds := a.search(startId, targetId)
-			path, err := a.constructPath(startId, targetId, pathIds)
+			if a.cancelled() {
+				return nil, a.cancellation()
+			}
 
+			paths, err := a.pathsBetween(startId, targetId, constraints)
+
+			// One pair of duplicates being in separate trees doesn't mean the others are.
 			if err != nil {
-				return errAnalysisNoPathsFound
+				continue
 			}
 
-			allPaths = append(allPaths, path)
+			for _, path := range paths {
+				allPaths = append(allPaths, PathResult{Ids: path, Start: a.employeeMap[startId], Target: a.employeeMap[targetId], Cost: a.pathCost(path)})
+			}
 		}
 	}
 
-	// Sort all of our calculated paths, push the shortest one to the front.
-	sort.Slice(allPaths, func(i int, j int) bool {
-		return len(allPaths[i]) < len(allPaths[j])
+	// A search that was stopped part way through can't be trusted, even if it found something.
+	if a.cancelled() {
+		return nil, a.cancellation()
+	}
+
+	if len(allPaths) == 0 && constraints.active() {
+		return nil, fmt.Errorf("%w %s", errAnalysisNoConstrainedPath, a.describeConstraints())
+	}
+
+	if len(allPaths) == 0 {
+		return nil, errAnalysisNoPathsFound
+	}
+
+	// Sort all of our calculated paths, push the shortest (or cheapest) one to the front.
+	// A stable sort keeps paths of equal length in chart order.
+	sort.SliceStable(allPaths, func(i int, j int) bool {
+		return allPaths[i].Cost < allPaths[j].Cost
 	})
 
-	// The shortest path gets turned into a string.
-	shortestPath, err := a.pathToString(allPaths[0])
+	// With duplicate names each pair contributes its own k paths, but k is a limit on the whole query.
+	if a.kShortestPaths > 0 && len(allPaths) > a.kShortestPaths {
+		allPaths = allPaths[:a.kShortestPaths]
+	}
 
-	if err != nil {
-		return err
+	return allPaths, nil
+}
+
+// A single path is output on its own, as it always has been.
+// Several candidates get numbered, with the details of each endpoint so it's clear which employees each one was between.
+func (a *organisationChartAnalyser) pathsToString(paths []PathResult) (string, error) {
+	if len(paths) == 1 {
+		path, err := a.pathToString(paths[0].Ids)
+		return path.String(), err
 	}
 
-	_, err = a.output.Write([]byte(shortestPath.String()))
+	lines := []string{fmt.Sprintf("%d candidate paths found, shortest first:", len(paths))}
 
-	if err != nil {
-		return err
+	for i, candidate := range paths {
+		path, err := a.pathToString(candidate.Ids)
+
+		if err != nil {
+			return "", err
+		}
+
+		lines = append(lines,
+			fmt.Sprintf("%d. %s", i+1, path.String()),
+			fmt.Sprintf("   start: %s | target: %s", describeEmployee(candidate.Start), describeEmployee(candidate.Target)),
+		)
 	}
 
-	return nil
+	return strings.Join(lines, "\n"), nil
 }
 
 // BFS algorithm.
-func (a *organisationChartAnalyser) search(startId int, targetId int) map[int]int {
+// Anything in the exclusions is treated as if it wasn't in the graph - this is what lets Yen's algorithm find alternative routes.
+func (a *organisationChartAnalyser) search(startId int, targetId int, excluded searchExclusions) map[int]int {
 	queue := []int{startId}
 
 	// Use a map for quicker lookup of seenIds.
@@ -104,11 +234,15 @@ func (a *organisationChartAnalyser) search(startId int, targetId int) map[int]in
 	// This map shows the actual 'hops' between nodes.
 	pathIds := make(map[int]int)
 
-	for len(queue) > 0 {
+	for len(queue) > 0 && !a.cancelled() {
 		currentId := queue[0]
 		queue = queue[1:] // Shift current item off start of queue.
 
 		for _, relationId := range a.adjList[currentId] {
+			if !excluded.allows(currentId, relationId) {
+				continue
+			}
+
 			// If the next node hasn't been seen, then add it to the queue.
 			// All nodes at a particular depth get added to the queue and get 'seen'.
 			if !seenIds[relationId] {
@@ -159,7 +293,6 @@ func (a *organisationChartAnalyser) constructPath(startId int, targetId int, pat
 // // I think you could use a bi-directional BFS for this in future for better performance maybe if it was critical.
 func (a *organisationChartAnalyser) pathToString(path []int) (strings.Builder, error) {
 	idMap := a.mapEmployeeIds()
-	managerMap := a.mapManagement()
 
 	var stringsPath strings.Builder
 	flowDirection := ""
@@ -168,10 +301,11 @@ func (a *organisationChartAnalyser) pathToString(path []int) (strings.Builder, e
 		current := path[i]
 		next := path[i+1]
 
-		if managerMap[current] == next {
-			flowDirection = "->"
-		} else if managerMap[next] == current {
-			flowDirection = "<-"
+		// Each type of relationship gets its own pair of arrows, so dotted line hops stand out from solid ones.
+		if relationshipType, ok := a.relationshipMap[[2]int{current, next}]; ok {
+			flowDirection = upArrows[relationshipType]
+		} else if relationshipType, ok := a.relationshipMap[[2]int{next, current}]; ok {
+			flowDirection = downArrows[relationshipType]
 		}
 
 		_, err := stringsPath.WriteString(fmt.Sprintf("%s %s ", idMap[current], flowDirection))
@@ -191,20 +325,25 @@ func (a *organisationChartAnalyser) pathToString(path []int) (strings.Builder, e
 	return stringsPath, nil
 }
 
-func (a *organisationChartAnalyser) validateNames(name1 string, name2 string) error {
+func (a *organisationChartAnalyser) validateNames(name1 string, name2 string) ([]int, []int, error) {
 	// Making an assumption here - I think working on duplicate name inputs is quite messy.
-	if name1 == name2 {
-		return errAnalysisDuplicateNameArgument
+	if normaliseName(name1) == normaliseName(name2) {
+		return nil, nil, errAnalysisDuplicateNameArgument
 	}
 
-	_, startExists := a.nameMap[name1]
-	_, endExists := a.nameMap[name2]
+	startIds, err := a.resolveSelector(name1, errAnalysisInvalidNameArgument)
 
-	if !startExists || !endExists {
-		return errAnalysisInvalidNameArgument
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return nil
+	targetIds, err := a.resolveSelector(name2, errAnalysisInvalidNameArgument)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return startIds, targetIds, nil
 }
 
 // Build adjacency list structure - this is what we'll iterate over with the breadth-first-search.
@@ -213,11 +352,14 @@ func (a *organisationChartAnalyser) mapEmployees() map[int][]int {
 	adjList := make(map[int][]int)
 
 	for _, employee := range a.chart {
-		adjList[employee.Id] = make([]int, 0)
+		// A report can appear before their manager, so don't wipe out links that have already been added.
+		if _, ok := adjList[employee.Id]; !ok {
+			adjList[employee.Id] = make([]int, 0)
+		}
 
-		if employee.ManagerId != 0 {
-			adjList[employee.Id] = append(adjList[employee.Id], employee.ManagerId)
-			adjList[employee.ManagerId] = append(adjList[employee.ManagerId], employee.Id)
+		for _, manager := range a.traversableManagers(employee) {
+			adjList[employee.Id] = append(adjList[employee.Id], manager.ManagerId)
+			adjList[manager.ManagerId] = append(adjList[manager.ManagerId], employee.Id)
 		}
 
 	}
@@ -225,26 +367,76 @@ func (a *organisationChartAnalyser) mapEmployees() map[int][]int {
 	return adjList
 }
 
-// Having a map of each employee and their direct report helps us determine the direction of the data flow.
-func (a *organisationChartAnalyser) mapManagement() map[int]int {
-	managerMap := make(map[int]int)
+// Having a map of each employee > manager link and its type helps us determine the direction of the data flow, and which arrow to draw.
+func (a *organisationChartAnalyser) mapRelationships() map[[2]int]model.RelationshipType {
+	relationshipMap := make(map[[2]int]model.RelationshipType)
 
 	for _, employee := range a.chart {
-		managerMap[employee.Id] = employee.ManagerId
+		for _, manager := range a.traversableManagers(employee) {
+			relationshipMap[[2]int{employee.Id, manager.ManagerId}] = manager.Type
+		}
 	}
 
-	return managerMap
+	return relationshipMap
+}
+
+// The managers a path is allowed to go through - only the relationship types the analyser was configured with.
+// If someone has more than one type of relationship with the same manager, only the first (solid line first) counts.
+func (a *organisationChartAnalyser) traversableManagers(employee model.Employee) []model.Relationship {
+	managers := make([]model.Relationship, 0)
+	seenIds := make(map[int]bool)
+
+	for _, manager := range employee.Managers() {
+		if !slices.Contains(a.relationshipTypes, manager.Type) || seenIds[manager.ManagerId] {
+			continue
+		}
+
+		seenIds[manager.ManagerId] = true
+		managers = append(managers, manager)
+	}
+
+	return managers
+}
+
+// The adjacency list loses the direction of each relationship, so queries that only go down the chart use this instead.
+func (a *organisationChartAnalyser) mapReports() map[int][]int {
+	reportsMap := make(map[int][]int)
+
+	for _, employee := range a.chart {
+		if employee.ManagerId != 0 {
+			reportsMap[employee.ManagerId] = append(reportsMap[employee.ManagerId], employee.Id)
+		}
+	}
+
+	return reportsMap
+}
+
+// Looks up a single employee by ID.
+func (a *organisationChartAnalyser) Employee(id int) (model.Employee, bool) {
+	employee, ok := a.employeeMap[id]
+
+	return employee, ok
 }
 
 // Create easy lookups to translate ID's to names.
 func (a *organisationChartAnalyser) mapEmployeeIds() map[int]string {
 	idMap := make(map[int]string)
 	for _, employee := range a.chart {
-		idMap[employee.Id] = fmt.Sprintf("%s (%d)", employee.Name, employee.Id)
+		idMap[employee.Id] = labelEmployee(employee)
 	}
 	return idMap
 }
 
+// How an employee appears in paths, chains and reports, e.g. "Hawkeye (16)".
+// Vacant positions are marked, so it's clear there's no one in them.
+func labelEmployee(employee model.Employee) string {
+	if employee.Vacant {
+		return fmt.Sprintf("%s (%d) [vacant]", employee.Name, employee.Id)
+	}
+
+	return fmt.Sprintf("%s (%d)", employee.Name, employee.Id)
+}
+
 // Create easy lookups to translate names to ID's - we need to have slices instead of a hashmap
 // Because names might not be unique.
 func (a *organisationChartAnalyser) mapEmployeeNames() map[string][]int {
@@ -254,3 +446,22 @@ func (a *organisationChartAnalyser) mapEmployeeNames() map[string][]int {
 	}
 	return nameMap
 }
+
+// Create easy lookups to translate ID's to the full employee record.
+func (a *organisationChartAnalyser) mapEmployeesById() map[int]model.Employee {
+	employeeMap := make(map[int]model.Employee)
+	for _, employee := range a.chart {
+		employeeMap[employee.Id] = employee
+	}
+	return employeeMap
+}
+
+// Names are normalised before being used as keys, so several spellings of a name share one entry.
+func (a *organisationChartAnalyser) mapNormalisedEmployeeNames() map[string][]int {
+	normalisedNameMap := make(map[string][]int)
+	for _, employee := range a.chart {
+		key := normaliseName(employee.Name)
+		normalisedNameMap[key] = append(normalisedNameMap[key], employee.Id)
+	}
+	return normalisedNameMap
+}
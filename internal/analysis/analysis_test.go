@@ -1,6 +1,7 @@
 package analysis
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/lsg93/org-chart-parser/internal/model"
@@ -17,10 +18,10 @@ var exampleOrgChart = model.OrganisationChart{
 	model.Employee{Id: 17, Name: "Catwoman", ManagerId: 6},
 }
 
-func setupTestAnalyser(chart model.OrganisationChart) (*organisationChartAnalyser, *testWriter) {
+func setupTestAnalyser(chart model.OrganisationChart, opts ...AnalyserOption) (*organisationChartAnalyser, *testWriter) {
 	// Output needs to go to a writer to make assertions against.
 	tw := &testWriter{}
-	return NewOrganisationChartAnalyser(tw, chart), tw
+	return NewOrganisationChartAnalyser(tw, chart, opts...), tw
 }
 
 type testWriter struct {
@@ -36,6 +37,7 @@ func TestAnalysisReturnsShortestPathAsString(t *testing.T) {
 
 	type testCase struct {
 		input          model.OrganisationChart
+		options        []AnalyserOption
 		employee1      string
 		employee2      string
 		expectedOutput string
@@ -54,6 +56,17 @@ func TestAnalysisReturnsShortestPathAsString(t *testing.T) {
 			employee2:      "Catwoman",
 			expectedOutput: "Batman (16) -> Black Widow (6) <- Catwoman (17)",
 		},
+		"through a vacancy": {
+			input: model.OrganisationChart{
+				model.Employee{Id: 1, Name: "CEO"},
+				model.Employee{Id: 2, Name: "TBH - Head of Engineering", ManagerId: 1, Vacant: true},
+				model.Employee{Id: 3, Name: "Engineer", ManagerId: 2},
+				model.Employee{Id: 4, Name: "Head of Sales", ManagerId: 1},
+			},
+			employee1:      "Engineer",
+			employee2:      "Head of Sales",
+			expectedOutput: "Engineer (3) -> TBH - Head of Engineering (2) [vacant] -> CEO (1) <- Head of Sales (4)",
+		},
 		"handles duplicates gracefully": {
 			input: model.OrganisationChart{
 				model.Employee{Id: 1, Name: "CEO", ManagerId: 0},
@@ -62,15 +75,32 @@ func TestAnalysisReturnsShortestPathAsString(t *testing.T) {
 				model.Employee{Id: 3, Name: "Boss", ManagerId: 0},
 				model.Employee{Id: 20, Name: "Minion", ManagerId: 2}, // This path would have 2 jumps.
 			},
+			options:        []AnalyserOption{WithShortestPathOnly()},
 			employee1:      "Minion",
 			employee2:      "CEO",
 			expectedOutput: "Minion (10) -> CEO (1)",
 		},
+		"lists every candidate when names are duplicated": {
+			input: model.OrganisationChart{
+				model.Employee{Id: 1, Name: "CEO", ManagerId: 0},
+				model.Employee{Id: 2, Name: "Boss", ManagerId: 1, Department: "Sales"},
+				model.Employee{Id: 20, Name: "Minion", ManagerId: 2, Title: "Rep", Department: "Sales"},
+				model.Employee{Id: 10, Name: "Minion", ManagerId: 1, Title: "Assistant"},
+				model.Employee{Id: 30, Name: "Minion", ManagerId: 0}, // Not connected to the CEO at all.
+			},
+			employee1: "Minion",
+			employee2: "CEO",
+			expectedOutput: `2 candidate paths found, shortest first:
+1. Minion (10) -> CEO (1)
+   start: Minion (10, Assistant) | target: CEO (1)
+2. Minion (20) -> Boss (2) -> CEO (1)
+   start: Minion (20, Rep, Sales) | target: CEO (1)`,
+		},
 	}
 
 	for desc, tc := range testCases {
 		t.Run(desc, func(t *testing.T) {
-			analyser, writer := setupTestAnalyser(tc.input)
+			analyser, writer := setupTestAnalyser(tc.input, tc.options...)
 			err := analyser.Analyse(tc.employee1, tc.employee2)
 			output := writer.contents
 
@@ -130,10 +160,75 @@ func TestAnalysisErrorsWithInvalidArguments(t *testing.T) {
 				t.Fatalf("There was no error analysing the given input when one should have occurred.")
 			}
 
-			if err != tc.expectedError {
+			if !errors.Is(err, tc.expectedError) {
 				t.Errorf("The received error '%s' was not equal to the expected output '%s'", output, tc.expectedError)
 			}
 		})
 	}
 
 }
+
+var matrixOrgChart = model.OrganisationChart{
+	model.Employee{Id: 1, Name: "Nick Fury"},
+	model.Employee{Id: 2, Name: "Iron Man", ManagerId: 1},
+	model.Employee{Id: 3, Name: "Captain Marvel", ManagerId: 1},
+	model.Employee{Id: 6, Name: "Black Widow", ManagerId: 2, Relationships: []model.Relationship{{ManagerId: 3, Type: model.DottedLine}}},
+	model.Employee{Id: 15, Name: "Daredevil", ManagerId: 3, Relationships: []model.Relationship{{ManagerId: 6, Type: model.Interim}}},
+	model.Employee{Id: 16, Name: "Hawkeye", ManagerId: 6},
+}
+
+func TestAnalysisTraversesConfiguredRelationshipTypes(t *testing.T) {
+	type testCase struct {
+		relationshipTypes []model.RelationshipType
+		expectedOutput    string
+	}
+
+	testCases := map[string]testCase{
+		"solid lines only by default": {
+			expectedOutput: "Hawkeye (16) -> Black Widow (6) -> Iron Man (2) -> Nick Fury (1) <- Captain Marvel (3) <- Daredevil (15)",
+		},
+		"with dotted lines": {
+			relationshipTypes: []model.RelationshipType{model.SolidLine, model.DottedLine},
+			expectedOutput:    "Hawkeye (16) -> Black Widow (6) ..> Captain Marvel (3) <- Daredevil (15)",
+		},
+		"with interim managers": {
+			relationshipTypes: []model.RelationshipType{model.SolidLine, model.Interim},
+			expectedOutput:    "Hawkeye (16) -> Black Widow (6) <~ Daredevil (15)",
+		},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			options := []AnalyserOption{}
+			if tc.relationshipTypes != nil {
+				options = append(options, WithRelationshipTypes(tc.relationshipTypes...))
+			}
+
+			analyser, writer := setupTestAnalyser(matrixOrgChart, options...)
+
+			if err := analyser.Analyse("Hawkeye", "Daredevil"); err != nil {
+				t.Fatalf("There was an error '%s' analysing the given input.", err)
+			}
+
+			if writer.contents != tc.expectedOutput {
+				t.Errorf("The received output '%s' was not equal to the expected output '%s'", writer.contents, tc.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestAnalysisHandlesReportsListedBeforeTheirManager(t *testing.T) {
+	analyser, writer := setupTestAnalyser(model.OrganisationChart{
+		model.Employee{Id: 2, Name: "VP", ManagerId: 1},
+		model.Employee{Id: 3, Name: "SWE", ManagerId: 1},
+		model.Employee{Id: 1, Name: "CEO"},
+	})
+
+	if err := analyser.Analyse("VP", "SWE"); err != nil {
+		t.Fatalf("There was an error '%s' analysing the given input.", err)
+	}
+
+	if expectedOutput := "VP (2) -> CEO (1) <- SWE (3)"; writer.contents != expectedOutput {
+		t.Errorf("The received output '%s' was not equal to the expected output '%s'", writer.contents, expectedOutput)
+	}
+}
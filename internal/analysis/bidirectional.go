@@ -0,0 +1,91 @@
+package analysis
+
+// The algorithm used for unweighted searches.
+type SearchStrategy int
+
+const (
+	BreadthFirstSearch  SearchStrategy = iota // the original single direction BFS
+	BidirectionalSearch                       // a BFS from both ends that stops where they meet
+)
+
+// Bidirectional BFS - searches out from the start and the target at the same time, always growing the smaller frontier.
+// On a chart shaped like a tree, each side only has to cover half the distance, so far fewer employees get visited.
+// It returns the same kind of previous hop map as search, so constructPath works with either.
+func (a *organisationChartAnalyser) bidirectionalSearch(startId int, targetId int, excluded searchExclusions) map[int]int {
+	// Forward hops point back towards the start, backward hops point on towards the target.
+	forwardIds := make(map[int]int)
+	backwardIds := make(map[int]int)
+
+	// Depths double up as the seen sets, and let us choose the best meeting point.
+	forwardDepths := map[int]int{startId: 0}
+	backwardDepths := map[int]int{targetId: 0}
+
+	forwardQueue := []int{startId}
+	backwardQueue := []int{targetId}
+
+	if startId == targetId {
+		return forwardIds
+	}
+
+	for len(forwardQueue) > 0 && len(backwardQueue) > 0 && !a.cancelled() {
+		var meetingId int
+		var met bool
+
+		if len(forwardQueue) <= len(backwardQueue) {
+			forwardQueue, meetingId, met = a.expandLevel(forwardQueue, forwardDepths, forwardIds, backwardDepths, func(from int, to int) bool {
+				return excluded.allows(from, to)
+			})
+		} else {
+			// Going backwards, the hop being checked is really from the neighbour to the current employee.
+			backwardQueue, meetingId, met = a.expandLevel(backwardQueue, backwardDepths, backwardIds, forwardDepths, func(from int, to int) bool {
+				return !excluded.ids[to] && !excluded.edges[[2]int{to, from}]
+			})
+		}
+
+		if met {
+			// Stitch the backward half on to the forward half, so the whole path can be walked back from the target.
+			for currentId := meetingId; currentId != targetId; currentId = backwardIds[currentId] {
+				forwardIds[backwardIds[currentId]] = currentId
+			}
+
+			return forwardIds
+		}
+	}
+
+	return forwardIds
+}
+
+// Expands a whole level of one side of the search, returning the next level.
+// Every meeting point found on this level is considered, and the one closest to the other side's origin wins,
+// as the first one found isn't necessarily on a shortest path.
+func (a *organisationChartAnalyser) expandLevel(queue []int, depths map[int]int, pathIds map[int]int, otherDepths map[int]int, allowed func(from int, to int) bool) ([]int, int, bool) {
+	next := make([]int, 0)
+	meetingId, met := 0, false
+
+	for _, currentId := range queue {
+		// A level can be most of a very large chart, so this is checked as it goes rather than once per level.
+		if a.cancelled() {
+			break
+		}
+
+		for _, relationId := range a.adjList[currentId] {
+			if !allowed(currentId, relationId) {
+				continue
+			}
+
+			if _, seen := depths[relationId]; seen {
+				continue
+			}
+
+			depths[relationId] = depths[currentId] + 1
+			pathIds[relationId] = currentId
+			next = append(next, relationId)
+
+			if otherDepth, ok := otherDepths[relationId]; ok && (!met || otherDepth < otherDepths[meetingId]) {
+				meetingId, met = relationId, true
+			}
+		}
+	}
+
+	return next, meetingId, met
+}
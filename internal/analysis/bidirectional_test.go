@@ -0,0 +1,117 @@
+package analysis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+func TestBidirectionalSearchMatchesBreadthFirstSearch(t *testing.T) {
+	type testCase struct {
+		input   model.OrganisationChart
+		options []AnalyserOption
+	}
+
+	testCases := map[string]testCase{
+		"example chart":             {input: exampleOrgChart},
+		"chart with a cycle":        {input: pentagonOrgChart},
+		"chart with dotted lines":   {input: matrixOrgChart, options: []AnalyserOption{WithRelationshipTypes(model.RelationshipTypes...)}},
+		"chart with separate trees": {input: model.OrganisationChart{{Id: 1, Name: "CEO"}, {Id: 2, Name: "VP", ManagerId: 1}, {Id: 3, Name: "CTO"}, {Id: 4, Name: "SWE", ManagerId: 3}}},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			bfs, _ := setupTestAnalyser(tc.input, tc.options...)
+			bidirectional, _ := setupTestAnalyser(tc.input, append(tc.options, WithSearchStrategy(BidirectionalSearch))...)
+
+			// Every pair should give a path of the same length, or fail the same way.
+			for _, start := range tc.input {
+				for _, target := range tc.input {
+					expected, expectedErr := bfs.shortestPath(start.Id, target.Id, searchExclusions{})
+					result, err := bidirectional.shortestPath(start.Id, target.Id, searchExclusions{})
+
+					if err != expectedErr || len(result) != len(expected) {
+						t.Errorf("From %d to %d the path was %v (%v), expected %v (%v)", start.Id, target.Id, result, err, expected, expectedErr)
+					}
+
+					if err == nil && (result[0] != start.Id || result[len(result)-1] != target.Id) {
+						t.Errorf("From %d to %d the path %v didn't run between them.", start.Id, target.Id, result)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestBidirectionalSearchHonoursExclusions(t *testing.T) {
+	analyser, _ := setupTestAnalyser(pentagonOrgChart, WithSearchStrategy(BidirectionalSearch))
+
+	path, err := analyser.shortestPath(1, 3, searchExclusions{ids: map[int]bool{2: true}})
+
+	if err != nil {
+		t.Fatalf("There was an error '%s' finding a path.", err)
+	}
+
+	if fmt.Sprint(path) != "[1 5 4 3]" {
+		t.Errorf("The path %v went through an excluded employee.", path)
+	}
+
+	// Excluding the edge in the direction of travel should push the search the other way round the cycle.
+	path, err = analyser.shortestPath(1, 3, searchExclusions{edges: map[[2]int]bool{{2, 3}: true}})
+
+	if err != nil {
+		t.Fatalf("There was an error '%s' finding a path.", err)
+	}
+
+	if fmt.Sprint(path) != "[1 5 4 3]" {
+		t.Errorf("The path %v used an excluded edge.", path)
+	}
+}
+
+// A complete tree where everyone has the same number of reports, numbered level by level from the root.
+func syntheticOrgChart(size int, span int) model.OrganisationChart {
+	chart := make(model.OrganisationChart, 0, size)
+	chart = append(chart, model.Employee{Id: 1, Name: "Employee 1"})
+
+	for id := 2; id <= size; id++ {
+		chart = append(chart, model.Employee{Id: id, Name: fmt.Sprintf("Employee %d", id), ManagerId: (id-2)/span + 1})
+	}
+
+	return chart
+}
+
+// Searches between the first and last employees of the tree - two leaves on opposite sides of the root,
+// which is about the worst case for a single direction BFS as it ends up visiting nearly everyone.
+func BenchmarkSearchStrategies(b *testing.B) {
+	strategies := map[string]SearchStrategy{
+		"bfs":           BreadthFirstSearch,
+		"bidirectional": BidirectionalSearch,
+	}
+
+	// Each chart is only built once its size is run, so running one size (e.g. -bench 'SearchStrategies/^10000$') doesn't build the 1M chart too.
+	for _, size := range []int{10_000, 100_000, 1_000_000} {
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+			chart := syntheticOrgChart(size, 5)
+			targetId := size
+
+			// Follow the first report down from the root to get the leftmost employee on the bottom level.
+			startId := 1
+			for (startId-1)*5+2 <= size {
+				startId = (startId-1)*5 + 2
+			}
+
+			for name, strategy := range strategies {
+				b.Run(name, func(b *testing.B) {
+					analyser := NewOrganisationChartAnalyser(nil, chart, WithSearchStrategy(strategy))
+
+					for b.Loop() {
+						if _, err := analyser.shortestPath(startId, targetId, searchExclusions{}); err != nil {
+							b.Fatal(err)
+						}
+					}
+				})
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+)
+
+var errAnalysisCancelled = errors.New("The search was stopped before it finished.")
+
+// Searches over very large charts can take a while, so servers and batch jobs can hand one a context to stop it early.
+// Analysers are shared, so the context goes on a copy for just the one query. Everything else in the copy is only ever read,
+// so it shares the original's maps rather than building its own.
+func (a *organisationChartAnalyser) withContext(ctx context.Context) *organisationChartAnalyser {
+	query := *a
+	query.ctx = ctx
+
+	return &query
+}
+
+// Checked on every employee a search visits. Without a context nothing is ever cancelled.
+func (a *organisationChartAnalyser) cancelled() bool {
+	if a.ctx == nil {
+		return false
+	}
+
+	select {
+	case <-a.ctx.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+// Still matches context.Canceled or context.DeadlineExceeded, so callers can tell which it was.
+func (a *organisationChartAnalyser) cancellation() error {
+	return errors.Join(errAnalysisCancelled, a.ctx.Err())
+}
+
+// The same as Paths, but gives up with the context's error as soon as the context is cancelled or its deadline passes.
+func (a *organisationChartAnalyser) PathsContext(ctx context.Context, name1 string, name2 string) ([]PathResult, error) {
+	return a.withContext(ctx).Paths(name1, name2)
+}
+
+// The same as Analyse, but gives up with the context's error as soon as the context is cancelled or its deadline passes.
+// Nothing is written unless the search finishes.
+func (a *organisationChartAnalyser) AnalyseContext(ctx context.Context, name1 string, name2 string) error {
+	return a.withContext(ctx).Analyse(name1, name2)
+}
@@ -0,0 +1,86 @@
+package analysis
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCancelledPathQueriesStop(t *testing.T) {
+	type testCase struct {
+		options []AnalyserOption
+	}
+
+	testCases := map[string]testCase{
+		"with a breadth first search":  {},
+		"with a bidirectional search":  {options: []AnalyserOption{WithSearchStrategy(BidirectionalSearch)}},
+		"with a weighted search":       {options: []AnalyserOption{WithWeightedSearch(DefaultCostRules())}},
+		"with all shortest paths":      {options: []AnalyserOption{WithAllShortestPaths()}},
+		"with the k shortest paths":    {options: []AnalyserOption{WithKShortestPaths(3)}},
+		"with a required employee":     {options: []AnalyserOption{WithRequiring("Super Ted")}},
+		"with an avoided employee too": {options: []AnalyserOption{WithAvoiding("#17"), WithKShortestPaths(2)}},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			analyser, output := setupTestAnalyser(exampleOrgChart, tc.options...)
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			if _, err := analyser.PathsContext(ctx, "Batman", "Super Ted"); !errors.Is(err, errAnalysisCancelled) || !errors.Is(err, context.Canceled) {
+				t.Errorf("The error '%v' was returned, but it was not the expected error '%v'", err, errAnalysisCancelled)
+			}
+
+			if err := analyser.AnalyseContext(ctx, "Batman", "Super Ted"); !errors.Is(err, context.Canceled) {
+				t.Errorf("The error '%v' was returned, but it was not the expected error '%v'", err, context.Canceled)
+			}
+
+			if output.contents != "" {
+				t.Errorf("A cancelled query wrote '%s', expected nothing.", output.contents)
+			}
+		})
+	}
+}
+
+func TestSearchesStopPartWayThroughWhenCancelled(t *testing.T) {
+	analyser, _ := setupTestAnalyser(exampleOrgChart)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if visited := analyser.withContext(ctx).search(16, 17, searchExclusions{}); len(visited) > 0 {
+		t.Errorf("A cancelled search still visited %d employees.", len(visited))
+	}
+
+	if visited := analyser.withContext(ctx).weightedSearch(16, 17, searchExclusions{}); len(visited) > 0 {
+		t.Errorf("A cancelled weighted search still visited %d employees.", len(visited))
+	}
+}
+
+func TestPathQueriesWithALiveContextMatchPaths(t *testing.T) {
+	analyser, _ := setupTestAnalyser(exampleOrgChart, WithAllShortestPaths())
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	expected, err := analyser.Paths("Batman", "Super Ted")
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	received, err := analyser.PathsContext(ctx, "Batman", "Super Ted")
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	if !slices.EqualFunc(resultIds(received), resultIds(expected), slices.Equal) {
+		t.Errorf("The paths %v were not equal to the expected paths %v", resultIds(received), resultIds(expected))
+	}
+
+	// The context was only for the one query - the analyser itself carries on without one.
+	if analyser.ctx != nil {
+		t.Errorf("The analyser kept the context from a query.")
+	}
+}
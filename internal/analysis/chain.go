@@ -0,0 +1,93 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+var (
+	errAnalysisUnknownName     = errors.New("The name provided as an argument does not exist in the organisation chart.")
+	errAnalysisUnknownEmployee = errors.New("The employee ID provided does not exist in the organisation chart.")
+	errAnalysisBrokenChain     = errors.New("The chain of command is broken - an employee references a manager who does not exist in the organisation chart.")
+	errAnalysisManagementCycle = errors.New("The chain of command loops back on itself - the organisation chart contains a management cycle.")
+)
+
+// A single step in a chain of command.
+// Level 0 is the employee the chain was requested for, level 1 is their manager, and so on up to the root.
+type ChainLink struct {
+	Level    int            `json:"level"`
+	Employee model.Employee `json:"employee"`
+}
+
+// Writes the chain of command for every employee with the given name.
+// Names aren't guaranteed to be unique, so each matching employee gets their own line.
+func (a *organisationChartAnalyser) AnalyseChainOfCommand(name string) error {
+	ids, err := a.resolveSelector(name, errAnalysisUnknownName)
+
+	if err != nil {
+		return err
+	}
+
+	lines := make([]string, 0, len(ids))
+
+	for _, id := range ids {
+		chain, err := a.ChainOfCommand(id)
+
+		if err != nil {
+			return err
+		}
+
+		lines = append(lines, a.chainToString(chain))
+	}
+
+	_, err = a.output.Write([]byte(strings.Join(lines, "\n")))
+
+	return err
+}
+
+// Walks the manager links from an employee up to the top of the chart.
+// The returned chain is ordered from the employee (level 0) to the root.
+func (a *organisationChartAnalyser) ChainOfCommand(id int) ([]ChainLink, error) {
+	employee, ok := a.employeeMap[id]
+
+	if !ok {
+		return nil, errAnalysisUnknownEmployee
+	}
+
+	chain := []ChainLink{{Level: 0, Employee: employee}}
+
+	// Keep track of who we've visited so that a cycle in the data produces an error instead of an infinite loop.
+	seenIds := map[int]bool{id: true}
+
+	for employee.ManagerId != 0 {
+		manager, ok := a.employeeMap[employee.ManagerId]
+
+		if !ok {
+			return chain, fmt.Errorf("%w %s (%d) reports to manager ID %d.", errAnalysisBrokenChain, employee.Name, employee.Id, employee.ManagerId)
+		}
+
+		if seenIds[manager.Id] {
+			return chain, fmt.Errorf("%w %s (%d) appears in their own chain of command.", errAnalysisManagementCycle, manager.Name, manager.Id)
+		}
+
+		seenIds[manager.Id] = true
+		chain = append(chain, ChainLink{Level: len(chain), Employee: manager})
+		employee = manager
+	}
+
+	return chain, nil
+}
+
+// Output follows the same convention as paths - arrows point in the direction of management flow.
+func (a *organisationChartAnalyser) chainToString(chain []ChainLink) string {
+	parts := make([]string, 0, len(chain))
+
+	for _, link := range chain {
+		parts = append(parts, labelEmployee(link.Employee))
+	}
+
+	return strings.Join(parts, " -> ")
+}
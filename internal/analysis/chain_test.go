@@ -0,0 +1,128 @@
+package analysis
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+func TestChainOfCommandWalksToTheRoot(t *testing.T) {
+	analyser, _ := setupTestAnalyser(exampleOrgChart)
+
+	chain, err := analyser.ChainOfCommand(16)
+
+	if err != nil {
+		t.Fatalf("There was an error '%s' building the chain of command.", err)
+	}
+
+	expectedIds := []int{16, 6, 2, 1}
+	ids := make([]int, 0, len(chain))
+
+	for i, link := range chain {
+		if link.Level != i {
+			t.Errorf("The link for %s had level %d, expected %d.", link.Employee.Name, link.Level, i)
+		}
+		ids = append(ids, link.Employee.Id)
+	}
+
+	if !slices.Equal(ids, expectedIds) {
+		t.Errorf("The chain %v was not equal to the expected chain %v", ids, expectedIds)
+	}
+}
+
+func TestAnalysisReturnsChainOfCommandAsString(t *testing.T) {
+	type testCase struct {
+		input          model.OrganisationChart
+		employee       string
+		expectedOutput string
+	}
+
+	testCases := map[string]testCase{
+		"from a leaf": {
+			input:          exampleOrgChart,
+			employee:       "Batman",
+			expectedOutput: "Batman (16) -> Black Widow (6) -> Gonzo the Great (2) -> Dangermouse (1)",
+		},
+		"from the root": {
+			input:          exampleOrgChart,
+			employee:       "Dangermouse",
+			expectedOutput: "Dangermouse (1)",
+		},
+		"with duplicate names": {
+			input: model.OrganisationChart{
+				model.Employee{Id: 1, Name: "CEO"},
+				model.Employee{Id: 2, Name: "Minion", ManagerId: 1},
+				model.Employee{Id: 3, Name: "Boss", ManagerId: 1},
+				model.Employee{Id: 4, Name: "Minion", ManagerId: 3},
+			},
+			employee:       "Minion",
+			expectedOutput: "Minion (2) -> CEO (1)\nMinion (4) -> Boss (3) -> CEO (1)",
+		},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			analyser, writer := setupTestAnalyser(tc.input)
+			err := analyser.AnalyseChainOfCommand(tc.employee)
+
+			if err != nil {
+				t.Fatalf("There was an error '%s' analysing the given input.", err)
+			}
+
+			if writer.contents != tc.expectedOutput {
+				t.Errorf("The received output '%s' was not equal to the expected output '%s'", writer.contents, tc.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestChainOfCommandErrorsWithInvalidData(t *testing.T) {
+	type testCase struct {
+		input         model.OrganisationChart
+		employee      string
+		expectedError error
+	}
+
+	testCases := map[string]testCase{
+		"Non-existent name as argument": {
+			input:         exampleOrgChart,
+			employee:      "Superman",
+			expectedError: errAnalysisUnknownName,
+		},
+		"When a manager does not exist": {
+			input: model.OrganisationChart{
+				model.Employee{Id: 1, Name: "CEO"},
+				model.Employee{Id: 2, Name: "VP", ManagerId: 5},
+				model.Employee{Id: 3, Name: "SWE", ManagerId: 2},
+			},
+			employee:      "SWE",
+			expectedError: errAnalysisBrokenChain,
+		},
+		"When management loops back on itself": {
+			input: model.OrganisationChart{
+				model.Employee{Id: 1, Name: "CEO", ManagerId: 3},
+				model.Employee{Id: 2, Name: "VP", ManagerId: 1},
+				model.Employee{Id: 3, Name: "SWE", ManagerId: 2},
+			},
+			employee:      "SWE",
+			expectedError: errAnalysisManagementCycle,
+		},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			analyser, _ := setupTestAnalyser(tc.input)
+			err := analyser.AnalyseChainOfCommand(tc.employee)
+
+			if err == nil {
+				t.Fatalf("There was no error analysing the given input when one should have occurred.")
+			}
+
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("The received error '%s' was not the expected error '%s'", err, tc.expectedError)
+			}
+		})
+	}
+}
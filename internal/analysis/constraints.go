@@ -0,0 +1,138 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+)
+
+var (
+	errAnalysisInvalidConstraint = errors.New("One of the people in the path constraints does not exist in the organisation chart.")
+	errAnalysisNoConstrainedPath = errors.New("No path between the given employees satisfies the constraints.")
+	errAnalysisViaWithSearchMode = errors.New("Paths through required employees are routed leg by leg, so only the shortest one can be found - they can't be combined with all or k shortest paths.")
+)
+
+// The avoid/require selectors, resolved to employee IDs.
+type pathConstraints struct {
+	avoided  map[int]bool
+	required [][]int // the path has to pass through one employee from each group, in order
+}
+
+func (c pathConstraints) active() bool {
+	return len(c.avoided) > 0 || len(c.required) > 0
+}
+
+// Constraints use the same selectors as the start and target, so they can be an ID, a name or an attribute like "department=Ops".
+// Unlike the endpoints, matching several employees is expected here - avoiding a department means avoiding everyone in it.
+func (a *organisationChartAnalyser) resolveConstraints() (pathConstraints, error) {
+	constraints := pathConstraints{avoided: make(map[int]bool)}
+
+	if len(a.requiring) > 0 && (a.allShortestPaths || a.kShortestPaths > 0) {
+		return constraints, errAnalysisViaWithSearchMode
+	}
+
+	for _, selector := range a.avoiding {
+		ids, err := a.matchSelector(selector, errAnalysisInvalidConstraint)
+
+		if err != nil {
+			return constraints, err
+		}
+
+		for _, id := range ids {
+			constraints.avoided[id] = true
+		}
+	}
+
+	for _, selector := range a.requiring {
+		ids, err := a.matchSelector(selector, errAnalysisInvalidConstraint)
+
+		if err != nil {
+			return constraints, err
+		}
+
+		constraints.required = append(constraints.required, ids)
+	}
+
+	return constraints, nil
+}
+
+func (a *organisationChartAnalyser) describeConstraints() string {
+	parts := make([]string, 0, 2)
+
+	if len(a.avoiding) > 0 {
+		parts = append(parts, fmt.Sprintf("Avoiding: %s.", strings.Join(a.avoiding, ", ")))
+	}
+
+	if len(a.requiring) > 0 {
+		parts = append(parts, fmt.Sprintf("Passing through: %s.", strings.Join(a.requiring, ", ")))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// Finds a simple path from start to target that passes through one employee from each required group, in order.
+// Each leg is the shortest route available once everything used by the earlier legs is excluded,
+// and where a group has several employees, the one giving the cheapest overall path is used.
+func (a *organisationChartAnalyser) routeThrough(startId int, targetId int, required [][]int, excluded searchExclusions) ([]int, error) {
+	if len(required) == 0 {
+		return a.shortestPath(startId, targetId, excluded)
+	}
+
+	var best []int
+
+	for _, waypointId := range required[0] {
+		if a.cancelled() {
+			break
+		}
+
+		// The target can only be a waypoint if it's the last one - otherwise the path would have to carry on past the end.
+		if (excluded.ids[waypointId] && waypointId != startId) || (waypointId == targetId && len(required) > 1) {
+			continue
+		}
+
+		// The first leg can't pass through the target on its way to the waypoint.
+		legExcluded := excluded
+
+		if waypointId != targetId {
+			legExcluded = excludingIds(excluded, targetId)
+		}
+
+		leg, err := a.shortestPath(startId, waypointId, legExcluded)
+
+		if err != nil {
+			continue
+		}
+
+		// Later legs can't revisit anything from this one, apart from the waypoint they start from.
+		rest, err := a.routeThrough(waypointId, targetId, required[1:], excludingIds(excluded, leg[:len(leg)-1]...))
+
+		if err != nil {
+			continue
+		}
+
+		path := append(leg[:len(leg)-1:len(leg)-1], rest...)
+
+		if best == nil || a.pathCost(path) < a.pathCost(best) {
+			best = path
+		}
+	}
+
+	if best == nil {
+		return nil, errAnalysisNoPathsFound
+	}
+
+	return best, nil
+}
+
+// Copies the exclusions with some extra IDs added, so the original can be reused.
+func excludingIds(excluded searchExclusions, ids ...int) searchExclusions {
+	extended := searchExclusions{ids: make(map[int]bool, len(excluded.ids)+len(ids)), edges: excluded.edges}
+	maps.Copy(extended.ids, excluded.ids)
+
+	for _, id := range ids {
+		extended.ids[id] = true
+	}
+
+	return extended
+}
@@ -0,0 +1,133 @@
+package analysis
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+func TestPathsHonourConstraints(t *testing.T) {
+	type testCase struct {
+		input        model.OrganisationChart
+		options      []AnalyserOption
+		employee1    string
+		employee2    string
+		expectedPath []int
+	}
+
+	withDottedLines := WithRelationshipTypes(model.SolidLine, model.DottedLine)
+
+	testCases := map[string]testCase{
+		"avoiding someone on the shortest path": {
+			input:        matrixOrgChart,
+			options:      []AnalyserOption{withDottedLines, WithAvoiding("Black Widow")},
+			employee1:    "Iron Man",
+			employee2:    "Daredevil",
+			expectedPath: []int{2, 1, 3, 15},
+		},
+		"requiring someone off the shortest path": {
+			input:        matrixOrgChart,
+			options:      []AnalyserOption{withDottedLines, WithRequiring("Nick Fury")},
+			employee1:    "Hawkeye",
+			employee2:    "Daredevil",
+			expectedPath: []int{16, 6, 2, 1, 3, 15},
+		},
+		"requiring someone already on the shortest path": {
+			input:        exampleOrgChart,
+			options:      []AnalyserOption{WithRequiring("#6")},
+			employee1:    "Batman",
+			employee2:    "Catwoman",
+			expectedPath: []int{16, 6, 17},
+		},
+		"requiring one of several people by attribute": {
+			input:        duplicateNameOrgChart,
+			options:      []AnalyserOption{WithRequiring("title=Director")},
+			employee1:    "#16",
+			employee2:    "#40",
+			expectedPath: []int{16, 6, 1, 7, 40},
+		},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			analyser, _ := setupTestAnalyser(tc.input, tc.options...)
+			results, err := analyser.Paths(tc.employee1, tc.employee2)
+
+			if err != nil {
+				t.Fatalf("There was an error '%s' finding paths.", err)
+			}
+
+			if !slices.Equal(results[0].Ids, tc.expectedPath) {
+				t.Errorf("The path %v was not equal to the expected path %v", results[0].Ids, tc.expectedPath)
+			}
+		})
+	}
+}
+
+func TestPathsErrorWhenConstraintsCantBeMet(t *testing.T) {
+	type testCase struct {
+		input         model.OrganisationChart
+		options       []AnalyserOption
+		employee1     string
+		employee2     string
+		expectedError error
+	}
+
+	testCases := map[string]testCase{
+		"avoiding the only route": {
+			input:         exampleOrgChart,
+			options:       []AnalyserOption{WithAvoiding("Black Widow")},
+			employee1:     "Batman",
+			employee2:     "Catwoman",
+			expectedError: errAnalysisNoConstrainedPath,
+		},
+		"avoiding the target by attribute": {
+			input:         duplicateNameOrgChart,
+			options:       []AnalyserOption{WithAvoiding("department=Intel")},
+			employee1:     "#16",
+			employee2:     "#40",
+			expectedError: errAnalysisNoConstrainedPath,
+		},
+		"requiring someone that can't be reached without doubling back": {
+			input:         exampleOrgChart,
+			options:       []AnalyserOption{WithRequiring("Dangermouse")},
+			employee1:     "Batman",
+			employee2:     "Catwoman",
+			expectedError: errAnalysisNoConstrainedPath,
+		},
+		"requiring someone with all shortest paths": {
+			input:         exampleOrgChart,
+			options:       []AnalyserOption{WithRequiring("Black Widow"), WithAllShortestPaths()},
+			employee1:     "Batman",
+			employee2:     "Catwoman",
+			expectedError: errAnalysisViaWithSearchMode,
+		},
+		"requiring someone with k shortest paths": {
+			input:         exampleOrgChart,
+			options:       []AnalyserOption{WithRequiring("Black Widow"), WithKShortestPaths(2)},
+			employee1:     "Batman",
+			employee2:     "Catwoman",
+			expectedError: errAnalysisViaWithSearchMode,
+		},
+		"with a constraint that doesn't exist": {
+			input:         exampleOrgChart,
+			options:       []AnalyserOption{WithAvoiding("#99")},
+			employee1:     "Batman",
+			employee2:     "Catwoman",
+			expectedError: errAnalysisInvalidConstraint,
+		},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			analyser, _ := setupTestAnalyser(tc.input, tc.options...)
+			_, err := analyser.Paths(tc.employee1, tc.employee2)
+
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("The error '%v' was not the expected error '%v'", err, tc.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package analysis
+
+import (
+	"bufio"
+	"container/heap"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+var (
+	errAnalysisInvalidCostRule = errors.New("One of the lines in the cost rules is not a valid 'name = cost' rule.")
+	errAnalysisUnknownCostRule = errors.New("One of the cost rules has a name that isn't recognised.")
+	errAnalysisNegativeCost    = errors.New("Cost rules can't be negative - weighted search only works with costs of zero or more.")
+)
+
+// How much each hop costs in a weighted search.
+// The cost of a hop is the cost for its direction, plus the cost for the type of relationship,
+// plus an extra cost if the hop crosses between departments.
+type CostRules struct {
+	Up              float64 // employee to manager
+	Down            float64 // manager to report
+	Relationships   map[model.RelationshipType]float64
+	CrossDepartment float64
+}
+
+// With the defaults every hop costs 1, so a weighted search gives the same results as the BFS.
+func DefaultCostRules() CostRules {
+	return CostRules{
+		Up:            1,
+		Down:          1,
+		Relationships: map[model.RelationshipType]float64{},
+	}
+}
+
+// Reads cost rules from a small text file, one 'name = cost' rule per line. Blank lines and # comments are ignored.
+// Anything not mentioned keeps its default:
+//
+//	up = 1
+//	down = 3
+//	dotted = 0.5
+//	cross-department = 2
+func ParseCostRules(input io.Reader) (CostRules, error) {
+	rules := DefaultCostRules()
+	scanner := bufio.NewScanner(input)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		line = strings.TrimSpace(line)
+
+		if line == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, "=")
+
+		if !ok {
+			return rules, fmt.Errorf("%w Line %d: '%s'.", errAnalysisInvalidCostRule, lineNumber, line)
+		}
+
+		name = strings.ToLower(strings.TrimSpace(name))
+		cost, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+
+		if err != nil {
+			return rules, fmt.Errorf("%w Line %d: '%s'.", errAnalysisInvalidCostRule, lineNumber, line)
+		}
+
+		if cost < 0 {
+			return rules, fmt.Errorf("%w Line %d: '%s'.", errAnalysisNegativeCost, lineNumber, line)
+		}
+
+		if err := rules.set(name, cost); err != nil {
+			return rules, fmt.Errorf("%w Line %d: '%s'.", err, lineNumber, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return rules, err
+	}
+
+	return rules, nil
+}
+
+func (rules *CostRules) set(name string, cost float64) error {
+	switch name {
+	case "up":
+		rules.Up = cost
+	case "down":
+		rules.Down = cost
+	case "cross-department":
+		rules.CrossDepartment = cost
+	default:
+		for _, relationshipType := range model.RelationshipTypes {
+			if relationshipType.String() == name {
+				rules.Relationships[relationshipType] = cost
+				return nil
+			}
+		}
+
+		return errAnalysisUnknownCostRule
+	}
+
+	return nil
+}
+
+// Works out the cost of moving between two neighbouring employees in the adjacency list.
+func (a *organisationChartAnalyser) hopCost(fromId int, toId int) float64 {
+	rules := *a.costRules
+	cost := 0.0
+
+	if relationshipType, ok := a.relationshipMap[[2]int{fromId, toId}]; ok {
+		cost = rules.Up + rules.Relationships[relationshipType]
+	} else if relationshipType, ok := a.relationshipMap[[2]int{toId, fromId}]; ok {
+		cost = rules.Down + rules.Relationships[relationshipType]
+	}
+
+	from, to := a.employeeMap[fromId], a.employeeMap[toId]
+
+	if from.Department != "" && to.Department != "" && from.Department != to.Department {
+		cost += rules.CrossDepartment
+	}
+
+	return cost
+}
+
+// Returns the total cost of a path - with no cost rules this is just the number of hops.
+func (a *organisationChartAnalyser) pathCost(path []int) float64 {
+	if a.costRules == nil {
+		return float64(len(path) - 1)
+	}
+
+	cost := 0.0
+
+	for i := 0; i < len(path)-1; i++ {
+		cost += a.hopCost(path[i], path[i+1])
+	}
+
+	return cost
+}
+
+// Dijkstra's algorithm, the weighted alternative to the BFS in search.
+// Like searchAll, every previous hop on a cheapest route is kept, so the same result can be used for one path or all of them.
+// Hops can be free, so an employee can still get another previous hop after it's been settled - the target included.
+func (a *organisationChartAnalyser) weightedSearch(startId int, targetId int, excluded searchExclusions) map[int][]int {
+	costs := map[int]float64{startId: 0}
+	pathIds := make(map[int][]int)
+	settled := make(map[int]bool)
+
+	queue := &costQueue{{id: startId, cost: 0}}
+
+	for queue.Len() > 0 && !a.cancelled() {
+		current := heap.Pop(queue).(costQueueItem)
+
+		// Everything left costs more than the target, so there are no more routes into it to find.
+		if targetCost, found := costs[targetId]; found && current.cost > targetCost+costEpsilon {
+			break
+		}
+
+		// The queue can hold stale entries for nodes that were later reached more cheaply.
+		if settled[current.id] {
+			continue
+		}
+
+		settled[current.id] = true
+
+		for _, relationId := range a.adjList[current.id] {
+			if !excluded.allows(current.id, relationId) {
+				continue
+			}
+
+			cost := current.cost + a.hopCost(current.id, relationId)
+			known, seen := costs[relationId]
+
+			switch {
+			case !seen || cost < known-costEpsilon:
+				costs[relationId] = cost
+				pathIds[relationId] = []int{current.id}
+				heap.Push(queue, costQueueItem{id: relationId, cost: cost})
+			case cost <= known+costEpsilon:
+				pathIds[relationId] = append(pathIds[relationId], current.id)
+			}
+		}
+	}
+
+	return pathIds
+}
+
+// Costs can be fractional, so "equal cost" allows for floating point error.
+const costEpsilon = 1e-9
+
+type costQueueItem struct {
+	id   int
+	cost float64
+}
+
+// A min-heap of employees by cost, for container/heap.
+type costQueue []costQueueItem
+
+func (q costQueue) Len() int           { return len(q) }
+func (q costQueue) Less(i, j int) bool { return q[i].cost < q[j].cost }
+func (q costQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *costQueue) Push(x any)        { *q = append(*q, x.(costQueueItem)) }
+
+func (q *costQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
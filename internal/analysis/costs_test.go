@@ -0,0 +1,163 @@
+package analysis
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+func TestParsingCostRules(t *testing.T) {
+	input := `
+	# Escalating is cheap, going sideways isn't.
+	up = 1
+	Down = 3
+	dotted = 0.5 # matrix managers are nearly as good
+	cross-department = 2
+	`
+
+	rules, err := ParseCostRules(strings.NewReader(input))
+
+	if err != nil {
+		t.Fatalf("There was an error '%s' parsing the cost rules.", err)
+	}
+
+	expected := CostRules{
+		Up:              1,
+		Down:            3,
+		Relationships:   map[model.RelationshipType]float64{model.DottedLine: 0.5},
+		CrossDepartment: 2,
+	}
+
+	if !reflect.DeepEqual(rules, expected) {
+		t.Errorf("The rules %+v were not equal to the expected rules %+v", rules, expected)
+	}
+}
+
+func TestParsingInvalidCostRulesErrors(t *testing.T) {
+	type testCase struct {
+		input         string
+		expectedError error
+	}
+
+	testCases := map[string]testCase{
+		"without an equals sign":  {input: "up 1", expectedError: errAnalysisInvalidCostRule},
+		"with a non numeric cost": {input: "up = cheap", expectedError: errAnalysisInvalidCostRule},
+		"with a negative cost":    {input: "down = -1", expectedError: errAnalysisNegativeCost},
+		"with an unknown name":    {input: "sideways = 2", expectedError: errAnalysisUnknownCostRule},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			_, err := ParseCostRules(strings.NewReader(tc.input))
+
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("The error '%v' was not the expected error '%v'", err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestWeightedSearchPrefersCheaperPaths(t *testing.T) {
+	type testCase struct {
+		rules        CostRules
+		expectedPath []int
+		expectedCost float64
+	}
+
+	withRules := func(change func(*CostRules)) CostRules {
+		rules := DefaultCostRules()
+		change(&rules)
+		return rules
+	}
+
+	testCases := map[string]testCase{
+		"with default costs it matches the BFS": {
+			rules:        DefaultCostRules(),
+			expectedPath: []int{16, 6, 3, 15},
+			expectedCost: 3,
+		},
+		"with expensive dotted lines": {
+			rules:        withRules(func(r *CostRules) { r.Relationships[model.DottedLine] = 5 }),
+			expectedPath: []int{16, 6, 2, 1, 3, 15},
+			expectedCost: 5,
+		},
+		"with expensive department crossings": {
+			rules:        withRules(func(r *CostRules) { r.CrossDepartment = 10 }),
+			expectedPath: []int{16, 6, 2, 1, 3, 15},
+			expectedCost: 5,
+		},
+	}
+
+	// Black Widow is in a different department to her dotted line manager, but the solid line route never changes department.
+	chart := slices.Clone(matrixOrgChart)
+	for i := range chart {
+		chart[i].Department = "Avengers"
+	}
+	chart[3].Department = "S.H.I.E.L.D."
+	chart[5].Department = "S.H.I.E.L.D."
+	chart[1].Department = "S.H.I.E.L.D."
+	chart[0].Department = ""
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			analyser, _ := setupTestAnalyser(chart, WithRelationshipTypes(model.SolidLine, model.DottedLine), WithWeightedSearch(tc.rules))
+			results, err := analyser.Paths("Hawkeye", "Daredevil")
+
+			if err != nil {
+				t.Fatalf("There was an error '%s' finding paths.", err)
+			}
+
+			if !slices.Equal(results[0].Ids, tc.expectedPath) || results[0].Cost != tc.expectedCost {
+				t.Errorf("The path %v with cost %v was not the expected path %v with cost %v", results[0].Ids, results[0].Cost, tc.expectedPath, tc.expectedCost)
+			}
+		})
+	}
+}
+
+func TestWeightedSearchFindsEveryCheapestPathWithFreeHops(t *testing.T) {
+	// Going up is free, so Coulson's dotted line straight to Fury costs the same as going all the way up the solid line.
+	chart := model.OrganisationChart{
+		model.Employee{Id: 1, Name: "Nick Fury"},
+		model.Employee{Id: 2, Name: "Maria Hill", ManagerId: 1},
+		model.Employee{Id: 3, Name: "Jasper Sitwell", ManagerId: 2},
+		model.Employee{Id: 4, Name: "Phil Coulson", ManagerId: 3, Relationships: []model.Relationship{{ManagerId: 1, Type: model.DottedLine}}},
+	}
+
+	testCases := map[string]CostRules{
+		"with free hops up":   {Up: 0, Down: 1, Relationships: map[model.RelationshipType]float64{}},
+		"with every hop free": {Up: 0, Down: 0, Relationships: map[model.RelationshipType]float64{}},
+	}
+
+	expected := [][]int{{4, 1}, {4, 3, 2, 1}}
+
+	for desc, rules := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			analyser, _ := setupTestAnalyser(chart, WithRelationshipTypes(model.SolidLine, model.DottedLine), WithWeightedSearch(rules), WithAllShortestPaths())
+			results, err := analyser.Paths("Phil Coulson", "Nick Fury")
+
+			if err != nil {
+				t.Fatalf("There was an error '%s' finding paths.", err)
+			}
+
+			paths := make([][]int, 0, len(results))
+
+			for _, result := range results {
+				if result.Cost != 0 {
+					t.Errorf("The path %v cost %v, when every path up should be free.", result.Ids, result.Cost)
+				}
+
+				paths = append(paths, result.Ids)
+			}
+
+			slices.SortFunc(paths, func(a, b []int) int { return len(a) - len(b) })
+
+			if !reflect.DeepEqual(paths, expected) {
+				t.Errorf("The paths %v were not the expected paths %v", paths, expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package analysis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+// Formats the diff can be written in.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+var (
+	errAnalysisUnknownFormat = errors.New("The output format provided is not supported - use text or json.")
+)
+
+// Everything that changed between an earlier snapshot of the chart and the one being analysed.
+// Employees are matched by ID, so someone who changes their name is a rename rather than a departure and a hire.
+type ChartDiff struct {
+	Hires       []model.Employee `json:"hires"`
+	Departures  []model.Employee `json:"departures"`
+	Renames     []Rename         `json:"renames"`
+	Moves       []Move           `json:"moves"`       // individuals who changed manager on their own
+	Relocations []Move           `json:"relocations"` // managers who changed manager and took some of their reports with them
+	Managers    []ManagerChanges `json:"managers"`
+}
+
+type Rename struct {
+	Employee     model.Employee `json:"employee"`
+	PreviousName string         `json:"previousName"`
+}
+
+// A change of solid line manager. For relocations, Reports is how many of the employee's reports (at any depth) moved with them.
+type Move struct {
+	Employee          model.Employee `json:"employee"`
+	PreviousManagerId int            `json:"previousManagerId"`
+	ManagerId         int            `json:"managerId"`
+	Reports           int            `json:"reports,omitempty"`
+}
+
+// Summary counts for a single manager. Moves in and out include relocations.
+type ManagerChanges struct {
+	ManagerId  int    `json:"managerId"` // 0 counts employees without a manager
+	Name       string `json:"name,omitempty"`
+	Hires      int    `json:"hires"`
+	Departures int    `json:"departures"`
+	MovesIn    int    `json:"movesIn"`
+	MovesOut   int    `json:"movesOut"`
+}
+
+// Writes the changes since the previous chart in the given format.
+func (a *organisationChartAnalyser) AnalyseDiff(previous model.OrganisationChart, format string) error {
+	diff := a.Diff(previous)
+
+	switch format {
+	case FormatText:
+		_, err := a.output.Write([]byte(diffToString(diff, a.employeeMap, mapById(previous))))
+		return err
+	case FormatJSON:
+		encoder := json.NewEncoder(a.output)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(diff)
+	default:
+		return errAnalysisUnknownFormat
+	}
+}
+
+func (a *organisationChartAnalyser) Diff(previous model.OrganisationChart) ChartDiff {
+	diff := ChartDiff{
+		Hires:       []model.Employee{},
+		Departures:  []model.Employee{},
+		Renames:     []Rename{},
+		Moves:       []Move{},
+		Relocations: []Move{},
+		Managers:    []ManagerChanges{},
+	}
+
+	before := mapById(previous)
+	beforeReports := mapReportsById(previous)
+	counts := make(map[int]*ManagerChanges)
+
+	count := func(managerId int) *ManagerChanges {
+		if counts[managerId] == nil {
+			manager, _ := lookupEmployee(managerId, a.employeeMap, before)
+			counts[managerId] = &ManagerChanges{ManagerId: managerId, Name: manager.Name}
+		}
+
+		return counts[managerId]
+	}
+
+	for _, id := range sortedIds(a.employeeMap) {
+		employee := a.employeeMap[id]
+		old, existed := before[id]
+
+		if !existed {
+			diff.Hires = append(diff.Hires, employee)
+			count(employee.ManagerId).Hires++
+			continue
+		}
+
+		if old.Name != employee.Name {
+			diff.Renames = append(diff.Renames, Rename{Employee: employee, PreviousName: old.Name})
+		}
+
+		if old.ManagerId == employee.ManagerId {
+			continue
+		}
+
+		move := Move{Employee: employee, PreviousManagerId: old.ManagerId, ManagerId: employee.ManagerId}
+
+		// Anyone still beneath the employee who was also beneath them before has moved with them.
+		previousTeam := descendants(beforeReports, id)
+
+		for reportId := range descendants(a.reportsMap, id) {
+			if previousTeam[reportId] {
+				move.Reports++
+			}
+		}
+
+		if move.Reports > 0 {
+			diff.Relocations = append(diff.Relocations, move)
+		} else {
+			diff.Moves = append(diff.Moves, move)
+		}
+
+		count(old.ManagerId).MovesOut++
+		count(employee.ManagerId).MovesIn++
+	}
+
+	for _, id := range sortedIds(before) {
+		if _, ok := a.employeeMap[id]; !ok {
+			diff.Departures = append(diff.Departures, before[id])
+			count(before[id].ManagerId).Departures++
+		}
+	}
+
+	for _, id := range sortedIds(counts) {
+		diff.Managers = append(diff.Managers, *counts[id])
+	}
+
+	return diff
+}
+
+func diffToString(diff ChartDiff, after map[int]model.Employee, before map[int]model.Employee) string {
+	manager := func(id int) string {
+		return describeManager(id, after, before)
+	}
+
+	sections := make([]string, 0)
+
+	addSection := func(title string, lines []string) {
+		if len(lines) > 0 {
+			sections = append(sections, fmt.Sprintf("%s (%d):\n  %s", title, len(lines), strings.Join(lines, "\n  ")))
+		}
+	}
+
+	lines := make([]string, 0)
+	for _, employee := range diff.Hires {
+		lines = append(lines, fmt.Sprintf("%s (%d), reporting to %s", employee.Name, employee.Id, manager(employee.ManagerId)))
+	}
+	addSection("Hires", lines)
+
+	lines = make([]string, 0)
+	for _, employee := range diff.Departures {
+		lines = append(lines, fmt.Sprintf("%s (%d), who reported to %s", employee.Name, employee.Id, manager(employee.ManagerId)))
+	}
+	addSection("Departures", lines)
+
+	lines = make([]string, 0)
+	for _, rename := range diff.Renames {
+		lines = append(lines, fmt.Sprintf("%s -> %s (%d)", rename.PreviousName, rename.Employee.Name, rename.Employee.Id))
+	}
+	addSection("Renames", lines)
+
+	lines = make([]string, 0)
+	for _, move := range diff.Moves {
+		lines = append(lines, fmt.Sprintf("%s (%d): %s -> %s", move.Employee.Name, move.Employee.Id, manager(move.PreviousManagerId), manager(move.ManagerId)))
+	}
+	addSection("Moves", lines)
+
+	lines = make([]string, 0)
+	for _, move := range diff.Relocations {
+		lines = append(lines, fmt.Sprintf("%s (%d) and %d reports: %s -> %s", move.Employee.Name, move.Employee.Id, move.Reports, manager(move.PreviousManagerId), manager(move.ManagerId)))
+	}
+	addSection("Subtree relocations", lines)
+
+	lines = make([]string, 0)
+	for _, changes := range diff.Managers {
+		lines = append(lines, fmt.Sprintf("%s: %d hires, %d departures, %d moved in, %d moved out", manager(changes.ManagerId), changes.Hires, changes.Departures, changes.MovesIn, changes.MovesOut))
+	}
+	addSection("By manager", lines)
+
+	if len(sections) == 0 {
+		return "No changes."
+	}
+
+	return strings.Join(sections, "\n\n")
+}
+
+func describeManager(id int, after map[int]model.Employee, before map[int]model.Employee) string {
+	if id == 0 {
+		return "no manager"
+	}
+
+	if employee, ok := lookupEmployee(id, after, before); ok {
+		return fmt.Sprintf("%s (%d)", employee.Name, id)
+	}
+
+	return fmt.Sprintf("unknown manager (%d)", id)
+}
+
+// Managers might only be in one of the two charts, so look in both - the later one first, in case they've been renamed.
+func lookupEmployee(id int, after map[int]model.Employee, before map[int]model.Employee) (model.Employee, bool) {
+	if employee, ok := after[id]; ok {
+		return employee, true
+	}
+
+	employee, ok := before[id]
+
+	return employee, ok
+}
+
+func mapById(chart model.OrganisationChart) map[int]model.Employee {
+	employees := make(map[int]model.Employee, len(chart))
+
+	for _, employee := range chart {
+		employees[employee.Id] = employee
+	}
+
+	return employees
+}
+
+// The same as the analyser's reportsMap, for a chart we don't have an analyser for.
+func mapReportsById(chart model.OrganisationChart) map[int][]int {
+	reports := make(map[int][]int)
+
+	for _, employee := range chart {
+		if employee.ManagerId != 0 {
+			reports[employee.ManagerId] = append(reports[employee.ManagerId], employee.Id)
+		}
+	}
+
+	return reports
+}
+
+// Everyone beneath an employee, at any depth. Cycles are fine - each employee is only visited once.
+func descendants(reports map[int][]int, id int) map[int]bool {
+	found := make(map[int]bool)
+	queue := slices.Clone(reports[id])
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if found[current] || current == id {
+			continue
+		}
+
+		found[current] = true
+		queue = append(queue, reports[current]...)
+	}
+
+	return found
+}
+
+func sortedIds[V any](m map[int]V) []int {
+	ids := make([]int, 0, len(m))
+
+	for id := range m {
+		ids = append(ids, id)
+	}
+
+	slices.Sort(ids)
+
+	return ids
+}
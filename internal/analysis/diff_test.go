@@ -0,0 +1,115 @@
+package analysis
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+// The example chart a month later - one hire, one departure, a rename, a move and a relocation.
+var laterExampleOrgChart = model.OrganisationChart{
+	model.Employee{Id: 1, Name: "Dangermouse"},
+	model.Employee{Id: 2, Name: "The Great Gonzo", ManagerId: 1},
+	model.Employee{Id: 3, Name: "Invisible Woman", ManagerId: 1},
+	model.Employee{Id: 6, Name: "Black Widow", ManagerId: 3},
+	model.Employee{Id: 12, Name: "Hit Girl", ManagerId: 2},
+	model.Employee{Id: 16, Name: "Batman", ManagerId: 6},
+	model.Employee{Id: 17, Name: "Catwoman", ManagerId: 6},
+	model.Employee{Id: 20, Name: "Mr Tickle", ManagerId: 3},
+}
+
+func TestDiffBetweenSnapshots(t *testing.T) {
+	analyser, _ := setupTestAnalyser(laterExampleOrgChart)
+	diff := analyser.Diff(exampleOrgChart)
+
+	expected := ChartDiff{
+		Hires:      []model.Employee{{Id: 20, Name: "Mr Tickle", ManagerId: 3}},
+		Departures: []model.Employee{{Id: 15, Name: "Super Ted", ManagerId: 3}},
+		Renames:    []Rename{{Employee: model.Employee{Id: 2, Name: "The Great Gonzo", ManagerId: 1}, PreviousName: "Gonzo the Great"}},
+		Moves:      []Move{{Employee: model.Employee{Id: 12, Name: "Hit Girl", ManagerId: 2}, PreviousManagerId: 3, ManagerId: 2}},
+		Relocations: []Move{
+			{Employee: model.Employee{Id: 6, Name: "Black Widow", ManagerId: 3}, PreviousManagerId: 2, ManagerId: 3, Reports: 2},
+		},
+		Managers: []ManagerChanges{
+			{ManagerId: 2, Name: "The Great Gonzo", MovesIn: 1, MovesOut: 1},
+			{ManagerId: 3, Name: "Invisible Woman", Hires: 1, Departures: 1, MovesIn: 1, MovesOut: 1},
+		},
+	}
+
+	if !reflect.DeepEqual(diff, expected) {
+		t.Errorf("The diff %+v was not equal to the expected diff %+v", diff, expected)
+	}
+}
+
+func TestAnalyseDiffWritesText(t *testing.T) {
+	type testCase struct {
+		previous       model.OrganisationChart
+		expectedOutput string
+	}
+
+	testCases := map[string]testCase{
+		"with changes": {
+			previous: exampleOrgChart,
+			expectedOutput: `Hires (1):
+  Mr Tickle (20), reporting to Invisible Woman (3)
+
+Departures (1):
+  Super Ted (15), who reported to Invisible Woman (3)
+
+Renames (1):
+  Gonzo the Great -> The Great Gonzo (2)
+
+Moves (1):
+  Hit Girl (12): Invisible Woman (3) -> The Great Gonzo (2)
+
+Subtree relocations (1):
+  Black Widow (6) and 2 reports: The Great Gonzo (2) -> Invisible Woman (3)
+
+By manager (2):
+  The Great Gonzo (2): 0 hires, 0 departures, 1 moved in, 1 moved out
+  Invisible Woman (3): 1 hires, 1 departures, 1 moved in, 1 moved out`,
+		},
+		"without changes": {
+			previous:       laterExampleOrgChart,
+			expectedOutput: "No changes.",
+		},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			analyser, tw := setupTestAnalyser(laterExampleOrgChart)
+
+			if err := analyser.AnalyseDiff(tc.previous, FormatText); err != nil {
+				t.Fatalf("An error '%s' was returned when none was expected", err)
+			}
+
+			if tw.contents != tc.expectedOutput {
+				t.Errorf("The received output '%s' was not equal to the expected output '%s'", tw.contents, tc.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestAnalyseDiffWritesJSON(t *testing.T) {
+	analyser, tw := setupTestAnalyser(laterExampleOrgChart)
+
+	if err := analyser.AnalyseDiff(exampleOrgChart, FormatJSON); err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	var decoded ChartDiff
+
+	if err := json.Unmarshal([]byte(tw.contents), &decoded); err != nil {
+		t.Fatalf("The output '%s' was not valid JSON: %s", tw.contents, err)
+	}
+
+	if !reflect.DeepEqual(decoded, analyser.Diff(exampleOrgChart)) {
+		t.Errorf("The decoded diff %+v was not equal to the diff %+v", decoded, analyser.Diff(exampleOrgChart))
+	}
+
+	if err := analyser.AnalyseDiff(exampleOrgChart, "xml"); err != errAnalysisUnknownFormat {
+		t.Errorf("The error '%v' was returned, but it was not the expected error '%v'", err, errAnalysisUnknownFormat)
+	}
+}
@@ -0,0 +1,32 @@
+package analysis
+
+import "errors"
+
+// The errors themselves aren't exported, so code outside the package (like the server) uses these to tell what kind of problem it has.
+
+// Something that was asked for isn't in the chart - a name, selector or ID, or a path or manager linking two people.
+func IsNotFound(err error) bool {
+	return isAny(err, errAnalysisUnknownName, errAnalysisUnknownEmployee, errAnalysisInvalidNameArgument, errAnalysisInvalidConstraint,
+		errAnalysisNoPathsFound, errAnalysisNoConstrainedPath, errAnalysisNoCommonManager)
+}
+
+// The question doesn't make sense as asked, e.g. a name that matches more than one person, a negative depth or a bad line in a script.
+func IsInvalidRequest(err error) bool {
+	return isAny(err, errAnalysisAmbiguousName, errAnalysisDuplicateNameArgument, errAnalysisInvalidDepth, errAnalysisUnknownFormat, errAnalysisInvalidReorgOperation,
+		errAnalysisInvalidCostRule, errAnalysisUnknownCostRule, errAnalysisNegativeCost, errAnalysisViaWithSearchMode)
+}
+
+// The chart itself is broken in a way that stops the question being answered, e.g. a management cycle.
+func IsBrokenChart(err error) bool {
+	return isAny(err, errAnalysisBrokenChain, errAnalysisManagementCycle, errAnalysisDuplicateId, errAnalysisSelfManaged)
+}
+
+func isAny(err error, targets ...error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,125 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+var (
+	errAnalysisNoCommonManager = errors.New("The employees provided don't share a manager - they're in separate trees of the organisation chart.")
+)
+
+// The most junior person that both employees report up to, following solid lines only.
+// If one of them manages the other (directly or not), that's the manager, and an employee's lowest common manager with themselves is themselves.
+func (a *organisationChartAnalyser) LowestCommonManager(id1 int, id2 int) (model.Employee, error) {
+	if id, ok := a.liftToCommonManager(id1, id2); ok {
+		return a.employeeMap[id], nil
+	}
+
+	first, err := a.ChainOfCommand(id1)
+
+	if err != nil {
+		return model.Employee{}, err
+	}
+
+	second, err := a.ChainOfCommand(id2)
+
+	if err != nil {
+		return model.Employee{}, err
+	}
+
+	inFirst := make(map[int]bool, len(first))
+
+	for _, link := range first {
+		inFirst[link.Employee.Id] = true
+	}
+
+	// The second chain is ordered from the employee upwards, so the first shared link is the lowest.
+	for _, link := range second {
+		if inFirst[link.Employee.Id] {
+			return link.Employee, nil
+		}
+	}
+
+	return model.Employee{}, fmt.Errorf("%w %s and %s have no manager in common.", errAnalysisNoCommonManager, labelEmployee(first[0].Employee), labelEmployee(second[0].Employee))
+}
+
+// With tables (e.g. from an index) the answer comes from jumping up the ancestor tables rather than walking both chains.
+// Anything the tables can't answer - no tables, an employee without an unbroken chain, or two separate trees - falls back to the chains,
+// which also give the right errors.
+func (a *organisationChartAnalyser) liftToCommonManager(id1 int, id2 int) (int, bool) {
+	depth1, ok1 := a.depths[id1]
+	depth2, ok2 := a.depths[id2]
+
+	if !ok1 || !ok2 {
+		return 0, false
+	}
+
+	if depth1 < depth2 {
+		id1, id2, depth1, depth2 = id2, id1, depth2, depth1
+	}
+
+	// Bring the deeper employee up to the same level, a power of two at a time.
+	for level, diff := 0, depth1-depth2; diff > 0; level, diff = level+1, diff>>1 {
+		if diff&1 == 1 {
+			id1 = a.ancestors[id1][level]
+		}
+	}
+
+	if id1 == id2 {
+		return id1, true
+	}
+
+	// Then take the biggest jumps that still leave them apart - their managers are then the first people they share.
+	for level := len(a.ancestors[id1]) - 1; level >= 0; level-- {
+		if level < len(a.ancestors[id2]) && a.ancestors[id1][level] != a.ancestors[id2][level] {
+			id1, id2 = a.ancestors[id1][level], a.ancestors[id2][level]
+		}
+	}
+
+	if len(a.ancestors[id1]) == 0 || len(a.ancestors[id2]) == 0 || a.ancestors[id1][0] != a.ancestors[id2][0] {
+		return 0, false
+	}
+
+	return a.ancestors[id1][0], true
+}
+
+// The depth and ancestor tables, built down from each root. Employees in a cycle or below a broken chain are never reached,
+// so they're left out.
+func (a *organisationChartAnalyser) mapAncestors() (map[int]int, map[int][]int) {
+	depths := make(map[int]int, len(a.employeeMap))
+	ancestors := make(map[int][]int, len(a.employeeMap))
+	queue := make([]int, 0)
+
+	for _, id := range sortedIds(a.employeeMap) {
+		if a.employeeMap[id].ManagerId == 0 {
+			depths[id], ancestors[id] = 0, []int{}
+			queue = append(queue, id)
+		}
+	}
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		for _, report := range a.reportsMap[id] {
+			if _, seen := depths[report]; seen || a.employeeMap[report].ManagerId != id {
+				continue
+			}
+
+			// The manager 2^(k+1) levels up is the one 2^k levels above the manager 2^k levels up.
+			jumps := []int{id}
+
+			for k := 0; k < len(ancestors[jumps[k]]); k++ {
+				jumps = append(jumps, ancestors[jumps[k]][k])
+			}
+
+			depths[report], ancestors[report] = depths[id]+1, jumps
+			queue = append(queue, report)
+		}
+	}
+
+	return depths, ancestors
+}
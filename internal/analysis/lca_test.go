@@ -0,0 +1,70 @@
+package analysis
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+func TestLowestCommonManager(t *testing.T) {
+	type testCase struct {
+		id1, id2   int
+		expectedId int
+	}
+
+	testCases := map[string]testCase{
+		"for employees in different teams":    {id1: 16, id2: 15, expectedId: 1},
+		"for employees with the same manager": {id1: 16, id2: 17, expectedId: 6},
+		"when one manages the other":          {id1: 2, id2: 17, expectedId: 2},
+		"for the same employee":               {id1: 12, id2: 12, expectedId: 12},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			analyser, _ := setupTestAnalyser(exampleOrgChart)
+			manager, err := analyser.LowestCommonManager(tc.id1, tc.id2)
+
+			if err != nil {
+				t.Fatalf("An error '%s' was returned when none was expected", err)
+			}
+
+			if manager.Id != tc.expectedId {
+				t.Errorf("The manager %d returned was not the expected manager %d", manager.Id, tc.expectedId)
+			}
+		})
+	}
+}
+
+func TestLowestCommonManagerErrors(t *testing.T) {
+	type testCase struct {
+		chart         model.OrganisationChart
+		id1, id2      int
+		expectedError error
+	}
+
+	testCases := map[string]testCase{
+		"with employees in separate trees": {
+			chart:         model.OrganisationChart{{Id: 1, Name: "CEO"}, {Id: 2, Name: "Other CEO"}},
+			id1:           1,
+			id2:           2,
+			expectedError: errAnalysisNoCommonManager,
+		},
+		"with an unknown employee": {
+			chart:         exampleOrgChart,
+			id1:           1,
+			id2:           99,
+			expectedError: errAnalysisUnknownEmployee,
+		},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			analyser, _ := setupTestAnalyser(tc.chart)
+
+			if _, err := analyser.LowestCommonManager(tc.id1, tc.id2); !errors.Is(err, tc.expectedError) {
+				t.Errorf("The error '%v' was returned, but it was not the expected error '%v'", err, tc.expectedError)
+			}
+		})
+	}
+}
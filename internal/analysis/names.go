@@ -0,0 +1,191 @@
+package analysis
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// How many suggestions get included in a "did you mean" error.
+const maxNameSuggestions = 3
+
+// A name from the chart that is close to one that couldn't be found.
+// Distance is an edit distance, so lower is better - 0 means every word matched, just in a different order.
+type NameSuggestion struct {
+	Name     string
+	Ids      []int
+	Distance int
+}
+
+// Folding table for the accented characters likely to turn up in names.
+// Without golang.org/x/text there's no Unicode decomposition available, so this covers the common Latin ones by hand.
+// Combining marks are stripped separately, so already-decomposed input is handled too.
+var diacriticFolds = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae", 'ç': "c", 'ć': "c", 'č': "c", 'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ğ': "g", 'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'į': "i", 'ı': "i",
+	'ł': "l", 'ľ': "l", 'ñ': "n", 'ń': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o", 'œ': "oe",
+	'ř': "r", 'ś': "s", 'š': "s", 'ş': "s", 'ß': "ss", 'ť': "t", 'ţ': "t", 'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u", 'ų': "u",
+	'ý': "y", 'ÿ': "y", 'ź': "z", 'ż': "z", 'ž': "z",
+}
+
+// Lowercases, folds diacritics and collapses whitespace so that "  Zoë  Smith" and "zoe smith" compare equal.
+func normaliseName(name string) string {
+	var builder strings.Builder
+
+	for _, r := range strings.ToLower(name) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+
+		if folded, ok := diacriticFolds[r]; ok {
+			builder.WriteString(folded)
+			continue
+		}
+
+		builder.WriteRune(r)
+	}
+
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
+
+// Finds the employees for a name - exact matches win, then normalised matches, then (if enabled) an unambiguous fuzzy match.
+// The error passed in is wrapped with any suggestions, so callers can keep returning the error that suits their arguments.
+func (a *organisationChartAnalyser) resolveName(name string, notFound error) ([]int, error) {
+	if ids, ok := a.nameMap[name]; ok {
+		return ids, nil
+	}
+
+	if ids, ok := a.normalisedNameMap[normaliseName(name)]; ok {
+		return ids, nil
+	}
+
+	suggestions := a.SuggestNames(name)
+
+	if len(suggestions) == 0 {
+		return nil, fmt.Errorf("%w '%s' could not be found.", notFound, name)
+	}
+
+	// Only auto-select when there's a single best candidate - a tie is as ambiguous as no match at all.
+	if a.fuzzyAutoSelect && (len(suggestions) == 1 || suggestions[0].Distance < suggestions[1].Distance) {
+		return suggestions[0].Ids, nil
+	}
+
+	limit := min(len(suggestions), maxNameSuggestions)
+	options := make([]string, 0, limit)
+
+	for _, suggestion := range suggestions[:limit] {
+		options = append(options, a.describeIds(suggestion.Name, suggestion.Ids))
+	}
+
+	return nil, fmt.Errorf("%w '%s' could not be found - did you mean %s?", notFound, name, strings.Join(options, " or "))
+}
+
+// Ranks the names in the chart by how closely they resemble the given name.
+// Names are compared after normalisation, using the lower of a whole-string edit distance and a word-by-word comparison
+// so that reordered or partial names ("Witch", "Witch Scarlet") still find their match.
+func (a *organisationChartAnalyser) SuggestNames(name string) []NameSuggestion {
+	query := normaliseName(name)
+	threshold := max(1, len([]rune(query))/3)
+
+	suggestions := make([]NameSuggestion, 0)
+
+	for candidate, ids := range a.normalisedNameMap {
+		distance := min(levenshtein(query, candidate), tokenDistance(query, candidate))
+
+		if distance > threshold {
+			continue
+		}
+
+		// Suggest the name as it is written in the chart rather than the normalised version.
+		suggestions = append(suggestions, NameSuggestion{Name: a.employeeMap[ids[0]].Name, Ids: ids, Distance: distance})
+	}
+
+	slices.SortFunc(suggestions, func(x NameSuggestion, y NameSuggestion) int {
+		if x.Distance != y.Distance {
+			return x.Distance - y.Distance
+		}
+		return strings.Compare(x.Name, y.Name)
+	})
+
+	return suggestions
+}
+
+func (a *organisationChartAnalyser) describeIds(name string, ids []int) string {
+	idStrings := make([]string, 0, len(ids))
+
+	for _, id := range ids {
+		idStrings = append(idStrings, fmt.Sprint(id))
+	}
+
+	return fmt.Sprintf("%s (%s)", name, strings.Join(idStrings, ", "))
+}
+
+// Every word in the query is matched against its closest word in the candidate.
+// Words in the candidate that nothing matched count as one edit each, so a partial name is close but not exact.
+func tokenDistance(query string, candidate string) int {
+	queryTokens := strings.Fields(query)
+	candidateTokens := strings.Fields(candidate)
+	used := make([]bool, len(candidateTokens))
+	distance := 0
+
+	for _, queryToken := range queryTokens {
+		best, bestIndex := len(queryToken), -1
+
+		for i, candidateToken := range candidateTokens {
+			if used[i] {
+				continue
+			}
+
+			if d := levenshtein(queryToken, candidateToken); d < best {
+				best, bestIndex = d, i
+			}
+		}
+
+		if bestIndex >= 0 {
+			used[bestIndex] = true
+		}
+
+		distance += best
+	}
+
+	for _, matched := range used {
+		if !matched {
+			distance++
+		}
+	}
+
+	return distance
+}
+
+// Classic two-row dynamic programming edit distance, over runes rather than bytes.
+func levenshtein(s string, t string) int {
+	a, b := []rune(s), []rune(t)
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
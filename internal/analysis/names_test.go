@@ -0,0 +1,120 @@
+package analysis
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+var accentedOrgChart = model.OrganisationChart{
+	model.Employee{Id: 1, Name: "Nick Fury"},
+	model.Employee{Id: 6, Name: "Black Widow", ManagerId: 1},
+	model.Employee{Id: 16, Name: "Hawkeye", ManagerId: 6},
+	model.Employee{Id: 17, Name: "Scarlet Witch", ManagerId: 6},
+	model.Employee{Id: 18, Name: "Zoë Ångström", ManagerId: 1},
+}
+
+func TestNormaliseName(t *testing.T) {
+	testCases := map[string]string{
+		"Scarlet Witch":      "scarlet witch",
+		"  scarlet   WITCH ": "scarlet witch",
+		"Zoë Ångström":       "zoe angstrom",
+		"Zoë":               "zoe",
+	}
+
+	for input, expected := range testCases {
+		if result := normaliseName(input); result != expected {
+			t.Errorf("The name '%s' was normalised to '%s', expected '%s'", input, result, expected)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	testCases := map[[2]string]int{
+		{"", ""}:                            0,
+		{"witch", "witch"}:                  0,
+		{"scarlett witch", "scarlet witch"}: 1,
+		{"kitten", "sitting"}:               3,
+		{"zoë", "zoe"}:                      1,
+	}
+
+	for input, expected := range testCases {
+		if result := levenshtein(input[0], input[1]); result != expected {
+			t.Errorf("The distance between '%s' and '%s' was %d, expected %d", input[0], input[1], result, expected)
+		}
+	}
+}
+
+func TestAnalysisMatchesNormalisedNames(t *testing.T) {
+	analyser, writer := setupTestAnalyser(accentedOrgChart)
+
+	if err := analyser.Analyse("scarlet  witch", "ZOE ANGSTROM"); err != nil {
+		t.Fatalf("There was an error '%s' analysing the given input.", err)
+	}
+
+	expectedOutput := "Scarlet Witch (17) -> Black Widow (6) -> Nick Fury (1) <- Zoë Ångström (18)"
+
+	if writer.contents != expectedOutput {
+		t.Errorf("The received output '%s' was not equal to the expected output '%s'", writer.contents, expectedOutput)
+	}
+}
+
+func TestSuggestNames(t *testing.T) {
+	analyser, _ := setupTestAnalyser(accentedOrgChart)
+
+	type testCase struct {
+		input         string
+		expectedNames []string
+	}
+
+	testCases := map[string]testCase{
+		"with a typo":          {input: "Scarlett Witch", expectedNames: []string{"Scarlet Witch"}},
+		"with a partial name":  {input: "Witch", expectedNames: []string{"Scarlet Witch"}},
+		"with reordered words": {input: "Witch Scarlet", expectedNames: []string{"Scarlet Witch"}},
+		"with nothing close":   {input: "Thanos", expectedNames: []string{}},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			names := make([]string, 0)
+			for _, suggestion := range analyser.SuggestNames(tc.input) {
+				names = append(names, suggestion.Name)
+			}
+
+			if !slices.Equal(names, tc.expectedNames) {
+				t.Errorf("The suggestions %v were not the expected suggestions %v", names, tc.expectedNames)
+			}
+		})
+	}
+}
+
+func TestUnknownNamesErrorWithSuggestions(t *testing.T) {
+	analyser, _ := setupTestAnalyser(accentedOrgChart)
+	err := analyser.Analyse("Scarlett Witch", "Hawkeye")
+
+	if !errors.Is(err, errAnalysisInvalidNameArgument) {
+		t.Fatalf("The error '%v' was not the expected error '%v'", err, errAnalysisInvalidNameArgument)
+	}
+
+	if !strings.Contains(err.Error(), "did you mean Scarlet Witch (17)?") {
+		t.Errorf("The error '%s' did not suggest the closest name.", err)
+	}
+}
+
+func TestFuzzyAutoSelect(t *testing.T) {
+	writer := &testWriter{}
+	analyser := NewOrganisationChartAnalyser(writer, accentedOrgChart, WithFuzzyAutoSelect())
+
+	if err := analyser.Analyse("Scarlett Witch", "Hawkeye"); err != nil {
+		t.Fatalf("There was an error '%s' analysing the given input.", err)
+	}
+
+	expectedOutput := "Scarlet Witch (17) -> Black Widow (6) <- Hawkeye (16)"
+
+	if writer.contents != expectedOutput {
+		t.Errorf("The received output '%s' was not equal to the expected output '%s'", writer.contents, expectedOutput)
+	}
+}
@@ -0,0 +1,89 @@
+package analysis
+
+import (
+	"io"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+// Options tweak how the analyser resolves and searches, without changing the constructor for everyone else.
+type AnalyserOption func(*organisationChartAnalyser)
+
+// When a name can't be found, use the closest fuzzy match instead of erroring - as long as there's only one.
+func WithFuzzyAutoSelect() AnalyserOption {
+	return func(a *organisationChartAnalyser) {
+		a.fuzzyAutoSelect = true
+	}
+}
+
+// When a selector matches more than one employee, error with the candidates rather than trying every combination.
+func WithStrictNames() AnalyserOption {
+	return func(a *organisationChartAnalyser) {
+		a.strictNames = true
+	}
+}
+
+// Only output the shortest path, rather than every candidate when names are duplicated.
+func WithShortestPathOnly() AnalyserOption {
+	return func(a *organisationChartAnalyser) {
+		a.shortestPathOnly = true
+	}
+}
+
+// Return every path that shares the shortest length, rather than the first one the BFS finds.
+func WithAllShortestPaths() AnalyserOption {
+	return func(a *organisationChartAnalyser) {
+		a.allShortestPaths = true
+	}
+}
+
+// Return up to k of the shortest simple paths, using Yen's algorithm.
+func WithKShortestPaths(k int) AnalyserOption {
+	return func(a *organisationChartAnalyser) {
+		a.kShortestPaths = k
+	}
+}
+
+// Choose which kinds of relationship a path may traverse - by default only solid lines are followed.
+// Chains of command, reports and stats always follow solid lines.
+func WithRelationshipTypes(types ...model.RelationshipType) AnalyserOption {
+	return func(a *organisationChartAnalyser) {
+		a.relationshipTypes = types
+	}
+}
+
+// Search with Dijkstra's algorithm instead of the BFS, so paths are ranked by the total cost of their hops.
+func WithWeightedSearch(rules CostRules) AnalyserOption {
+	return func(a *organisationChartAnalyser) {
+		a.costRules = &rules
+	}
+}
+
+// Paths must not pass through anyone matching these selectors (IDs, names or attributes like "department=Ops").
+func WithAvoiding(selectors ...string) AnalyserOption {
+	return func(a *organisationChartAnalyser) {
+		a.avoiding = append(a.avoiding, selectors...)
+	}
+}
+
+// Paths must pass through someone matching each of these selectors, in the order given.
+func WithRequiring(selectors ...string) AnalyserOption {
+	return func(a *organisationChartAnalyser) {
+		a.requiring = append(a.requiring, selectors...)
+	}
+}
+
+// Choose the algorithm for unweighted searches. Weighted searches always use Dijkstra,
+// and --all style searches always use a single direction BFS, as they need every route into each employee.
+func WithSearchStrategy(strategy SearchStrategy) AnalyserOption {
+	return func(a *organisationChartAnalyser) {
+		a.searchStrategy = strategy
+	}
+}
+
+// Send the written analysis somewhere other than the writer given to the constructor.
+func WithOutput(output io.Writer) AnalyserOption {
+	return func(a *organisationChartAnalyser) {
+		a.output = output
+	}
+}
@@ -0,0 +1,365 @@
+package analysis
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+var (
+	errAnalysisInvalidReorgOperation = errors.New("One of the lines in the reorg script is not a valid operation - use 'move X under Y', 'remove X', 'rename X to NAME', 'add NAME under Y' or 'add vacancy [TITLE] under Y'.")
+	errAnalysisInvalidReorganisation = errors.New("The reorganisation would leave the chart in an invalid state:")
+)
+
+type ReorgOperationKind int
+
+const (
+	MoveEmployee   ReorgOperationKind = iota // move Subject under Manager
+	RemoveEmployee                           // remove Subject, with their reports moving up to Subject's manager
+	RenameEmployee                           // rename Subject to Name
+	AddEmployee                              // add a new employee called Subject under Manager
+	AddVacancy                               // add an unfilled position with the title Subject (which can be blank) under Manager
+)
+
+// A single step in a reorg script. Subject and Manager are selectors, apart from when adding, where Subject is the new name or title,
+// and when renaming, where the new name is in Name.
+type ReorgOperation struct {
+	Kind    ReorgOperationKind
+	Subject string
+	Manager string
+	Name    string
+	Line    int // the line of the script the operation came from, for errors
+}
+
+// Reads a reorg script, one operation per line. Names with spaces need quoting, and anything after a # on its own is a comment:
+//
+//	move "Black Widow" under "Nick Fury"
+//	remove Hawkeye  # their reports move up to Hawkeye's manager
+//	rename "Captain Marvel" to "Carol Danvers"
+//	add vacancy "Head of Ops" under #1
+//	add "Maria Hill" under title="Head of Ops"
+func ParseReorgScript(input io.Reader) ([]ReorgOperation, error) {
+	operations := make([]ReorgOperation, 0)
+	scanner := bufio.NewScanner(input)
+	lineNumber := 0
+
+	for scanner.Scan() {
+		lineNumber++
+		tokens, ok := splitScriptLine(scanner.Text())
+
+		if !ok {
+			return nil, fmt.Errorf("%w Line %d has a quote that is never closed.", errAnalysisInvalidReorgOperation, lineNumber)
+		}
+
+		if len(tokens) == 0 {
+			continue
+		}
+
+		operation, ok := parseReorgOperation(tokens)
+
+		if !ok {
+			return nil, fmt.Errorf("%w Line %d: '%s'.", errAnalysisInvalidReorgOperation, lineNumber, strings.TrimSpace(scanner.Text()))
+		}
+
+		operation.Line = lineNumber
+		operations = append(operations, operation)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return operations, nil
+}
+
+func parseReorgOperation(tokens []string) (ReorgOperation, bool) {
+	keyword := func(i int, word string) bool {
+		return i < len(tokens) && strings.EqualFold(tokens[i], word)
+	}
+
+	switch {
+	case keyword(0, "move") && len(tokens) == 4 && keyword(2, "under"):
+		return ReorgOperation{Kind: MoveEmployee, Subject: tokens[1], Manager: tokens[3]}, true
+	case keyword(0, "remove") && len(tokens) == 2:
+		return ReorgOperation{Kind: RemoveEmployee, Subject: tokens[1]}, true
+	case keyword(0, "rename") && len(tokens) == 4 && keyword(2, "to"):
+		return ReorgOperation{Kind: RenameEmployee, Subject: tokens[1], Name: tokens[3]}, true
+	case keyword(0, "add") && keyword(1, "vacancy") && len(tokens) == 4 && keyword(2, "under"):
+		return ReorgOperation{Kind: AddVacancy, Manager: tokens[3]}, true
+	case keyword(0, "add") && keyword(1, "vacancy") && len(tokens) == 5 && keyword(3, "under"):
+		return ReorgOperation{Kind: AddVacancy, Subject: tokens[2], Manager: tokens[4]}, true
+	case keyword(0, "add") && len(tokens) == 4 && keyword(2, "under"):
+		return ReorgOperation{Kind: AddEmployee, Subject: tokens[1], Manager: tokens[3]}, true
+	default:
+		return ReorgOperation{}, false
+	}
+}
+
+// Splits a line into words, keeping anything in double quotes together.
+// A # at the start of a word, followed by a space or the end of the line, starts a comment - so "#17" is still a selector.
+func splitScriptLine(line string) ([]string, bool) {
+	tokens := make([]string, 0)
+	runes := []rune(line)
+	var current strings.Builder
+	inQuotes, inToken := false, false
+
+	for i, r := range runes {
+		switch {
+		case r == '"':
+			inQuotes, inToken = !inQuotes, true
+		case inQuotes:
+			current.WriteRune(r)
+		case unicode.IsSpace(r):
+			if inToken {
+				tokens = append(tokens, current.String())
+				current.Reset()
+				inToken = false
+			}
+		case r == '#' && !inToken && (i+1 == len(runes) || unicode.IsSpace(runes[i+1])):
+			return tokens, true
+		default:
+			current.WriteRune(r)
+			inToken = true
+		}
+	}
+
+	if inToken {
+		tokens = append(tokens, current.String())
+	}
+
+	return tokens, !inQuotes
+}
+
+// Applies the operations in order to a copy of the chart - the analyser's own chart is left alone.
+// Selectors are resolved against the chart as it is at that point in the script, so later operations can refer to people added earlier.
+// The result has to pass Validate, so a chart that comes back can be saved as it is.
+func (a *organisationChartAnalyser) Reorganise(operations []ReorgOperation) (model.OrganisationChart, error) {
+	chart := slices.Clone(a.chart)
+
+	for _, operation := range operations {
+		current := a.derive(chart)
+		var err error
+
+		switch operation.Kind {
+		case MoveEmployee:
+			chart, err = current.moveEmployee(operation)
+		case RemoveEmployee:
+			chart, err = current.removeEmployee(operation)
+		case RenameEmployee:
+			chart, err = current.renameEmployee(operation)
+		case AddEmployee, AddVacancy:
+			chart, err = current.addEmployee(operation)
+		}
+
+		// Operations that didn't come from a script don't have a line to point at.
+		if err != nil && operation.Line > 0 {
+			return nil, fmt.Errorf("Line %d of the reorg script: %w", operation.Line, err)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := a.derive(chart).Validate(); err != nil {
+		return nil, fmt.Errorf("%w\n%w", errAnalysisInvalidReorganisation, err)
+	}
+
+	return chart, nil
+}
+
+func (a *organisationChartAnalyser) moveEmployee(operation ReorgOperation) (model.OrganisationChart, error) {
+	id, err := a.resolveOne(operation.Subject)
+
+	if err != nil {
+		return nil, err
+	}
+
+	managerId, err := a.resolveOne(operation.Manager)
+
+	if err != nil {
+		return nil, err
+	}
+
+	chart := slices.Clone(a.chart)
+	chart[a.indexOf(id)].ManagerId = managerId
+
+	return chart, nil
+}
+
+func (a *organisationChartAnalyser) removeEmployee(operation ReorgOperation) (model.OrganisationChart, error) {
+	id, err := a.resolveOne(operation.Subject)
+
+	if err != nil {
+		return nil, err
+	}
+
+	removed := a.employeeMap[id]
+	chart := make(model.OrganisationChart, 0, len(a.chart)-1)
+
+	for _, employee := range a.chart {
+		if employee.Id == id {
+			continue
+		}
+
+		if employee.ManagerId == id {
+			employee.ManagerId = removed.ManagerId
+		}
+
+		// Secondary lines to the removed employee just go.
+		employee.Relationships = slices.DeleteFunc(slices.Clone(employee.Relationships), func(r model.Relationship) bool { return r.ManagerId == id })
+
+		if len(employee.Relationships) == 0 {
+			employee.Relationships = nil
+		}
+
+		chart = append(chart, employee)
+	}
+
+	return chart, nil
+}
+
+func (a *organisationChartAnalyser) renameEmployee(operation ReorgOperation) (model.OrganisationChart, error) {
+	id, err := a.resolveOne(operation.Subject)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(operation.Name) == "" {
+		return nil, fmt.Errorf("%w Someone can't be renamed to a blank name.", errAnalysisInvalidReorgOperation)
+	}
+
+	chart := slices.Clone(a.chart)
+	employee := &chart[a.indexOf(id)]
+
+	// Giving a vacant position someone's name fills it.
+	employee.Name, employee.Vacant = operation.Name, false
+
+	return chart, nil
+}
+
+func (a *organisationChartAnalyser) addEmployee(operation ReorgOperation) (model.OrganisationChart, error) {
+	managerId, err := a.resolveOne(operation.Manager)
+
+	if err != nil {
+		return nil, err
+	}
+
+	employee := model.Employee{Id: a.nextId(), Name: operation.Subject, ManagerId: managerId}
+
+	if operation.Kind == AddVacancy {
+		employee.Name, employee.Title, employee.Vacant = "Vacancy", operation.Subject, true
+	}
+
+	return append(slices.Clone(a.chart), employee), nil
+}
+
+// Operations act on one employee, so selectors have to be unambiguous whatever the name matching options are.
+func (a *organisationChartAnalyser) resolveOne(selector string) (int, error) {
+	ids, err := a.matchSelector(selector, errAnalysisUnknownName)
+
+	if err != nil {
+		return 0, err
+	}
+
+	if len(ids) > 1 {
+		return 0, fmt.Errorf("%w '%s' matches %s.", errAnalysisAmbiguousName, selector, a.describeCandidates(ids))
+	}
+
+	return ids[0], nil
+}
+
+func (a *organisationChartAnalyser) indexOf(id int) int {
+	return slices.IndexFunc(a.chart, func(e model.Employee) bool { return e.Id == id })
+}
+
+func (a *organisationChartAnalyser) nextId() int {
+	next := 1
+
+	for id := range a.employeeMap {
+		next = max(next, id+1)
+	}
+
+	return next
+}
+
+// Applies the script and writes what would change - the diff, how the stats move, and the new chains of command for anyone who moved.
+// Nothing is written if the result wouldn't be a valid chart.
+func (a *organisationChartAnalyser) AnalyseReorg(operations []ReorgOperation) error {
+	chart, err := a.Reorganise(operations)
+
+	if err != nil {
+		return err
+	}
+
+	reorganised := a.derive(chart)
+	diff := reorganised.Diff(a.chart)
+	sections := []string{
+		fmt.Sprintf("Applied %d operations.", len(operations)),
+		diffToString(diff, reorganised.employeeMap, a.employeeMap),
+		"Stats:\n  " + strings.Join(statsDeltaLines(a.Stats(), reorganised.Stats()), "\n  "),
+	}
+
+	moved := append(slices.Clone(diff.Moves), diff.Relocations...)
+	slices.SortFunc(moved, func(x, y Move) int { return x.Employee.Id - y.Employee.Id })
+
+	if len(moved) > 0 {
+		chains := make([]string, 0, len(moved))
+
+		// Chains can come back with an error part way up if the original chart was broken - whatever was found is still worth showing.
+		for _, move := range moved {
+			before, _ := a.ChainOfCommand(move.Employee.Id)
+			after, _ := reorganised.ChainOfCommand(move.Employee.Id)
+			chains = append(chains, fmt.Sprintf("%s (%d)\n    before: %s\n    after:  %s", move.Employee.Name, move.Employee.Id, a.chainToString(before), reorganised.chainToString(after)))
+		}
+
+		sections = append(sections, "Changed chains of command:\n  "+strings.Join(chains, "\n  "))
+	}
+
+	_, err = a.output.Write([]byte(strings.Join(sections, "\n\n")))
+
+	return err
+}
+
+// One line per stat, e.g. "Headcount: 8 -> 9 (+1)".
+func statsDeltaLines(before ChartStats, after ChartStats) []string {
+	type delta struct {
+		label         string
+		before, after float64
+		format        string
+	}
+
+	deltas := []delta{
+		{"Headcount", float64(before.Headcount), float64(after.Headcount), "%.0f"},
+		{"Vacancies", float64(len(before.Vacancies)), float64(len(after.Vacancies)), "%.0f"},
+		{"Roots", float64(before.Roots), float64(after.Roots), "%.0f"},
+		{"Hierarchy depth", float64(before.Depth), float64(after.Depth), "%.0f"},
+		{"Widest level size", float64(before.WidestLevelSize), float64(after.WidestLevelSize), "%.0f"},
+		{"Average distance to root", before.AverageDistanceToRoot, after.AverageDistanceToRoot, "%.2f"},
+		{"Leaf ratio", before.LeafRatio, after.LeafRatio, "%.2f"},
+		{"Managers", float64(before.SpanOfControl.Managers), float64(after.SpanOfControl.Managers), "%.0f"},
+		{"Median span of control", before.SpanOfControl.Median, after.SpanOfControl.Median, "%.1f"},
+		{"Max span of control", float64(before.SpanOfControl.Max), float64(after.SpanOfControl.Max), "%.0f"},
+	}
+
+	lines := make([]string, 0, len(deltas))
+
+	for _, d := range deltas {
+		from, to, change := fmt.Sprintf(d.format, d.before), fmt.Sprintf(d.format, d.after), fmt.Sprintf("%+"+d.format[1:], d.after-d.before)
+
+		if from == to {
+			lines = append(lines, fmt.Sprintf("%s: %s (no change)", d.label, from))
+		} else {
+			lines = append(lines, fmt.Sprintf("%s: %s -> %s (%s)", d.label, from, to, change))
+		}
+	}
+
+	return lines
+}
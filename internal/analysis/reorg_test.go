@@ -0,0 +1,227 @@
+package analysis
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+func parseTestReorgScript(t *testing.T, script string) []ReorgOperation {
+	operations, err := ParseReorgScript(strings.NewReader(script))
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	return operations
+}
+
+func TestParsingReorgScripts(t *testing.T) {
+	operations := parseTestReorgScript(t, `# Q3 reorg
+move "Black Widow" under #3
+remove Hawkeye   # reports move up
+rename "Nick Fury" to "Director Fury"
+
+add vacancy under "name=Nick Fury"
+add vacancy "Head of Ops" under #1
+ADD "Maria Hill" under title="Head of Ops"`)
+
+	expected := []ReorgOperation{
+		{Kind: MoveEmployee, Subject: "Black Widow", Manager: "#3", Line: 2},
+		{Kind: RemoveEmployee, Subject: "Hawkeye", Line: 3},
+		{Kind: RenameEmployee, Subject: "Nick Fury", Name: "Director Fury", Line: 4},
+		{Kind: AddVacancy, Manager: "name=Nick Fury", Line: 6},
+		{Kind: AddVacancy, Subject: "Head of Ops", Manager: "#1", Line: 7},
+		{Kind: AddEmployee, Subject: "Maria Hill", Manager: "title=Head of Ops", Line: 8},
+	}
+
+	if !reflect.DeepEqual(operations, expected) {
+		t.Errorf("The operations %+v were not equal to the expected operations %+v", operations, expected)
+	}
+}
+
+func TestParsingInvalidReorgScriptsErrors(t *testing.T) {
+	scripts := map[string]string{
+		"with an unknown operation": "promote Hawkeye",
+		"without a manager":         "move Hawkeye",
+		"with an unclosed quote":    `remove "Black Widow`,
+		"without a new name":        "rename Hawkeye",
+	}
+
+	for desc, script := range scripts {
+		t.Run(desc, func(t *testing.T) {
+			if _, err := ParseReorgScript(strings.NewReader(script)); !errors.Is(err, errAnalysisInvalidReorgOperation) {
+				t.Errorf("The error '%v' was returned, but it was not the expected error '%v'", err, errAnalysisInvalidReorgOperation)
+			}
+		})
+	}
+}
+
+func TestReorganisingLeavesTheOriginalChartAlone(t *testing.T) {
+	analyser, _ := setupTestAnalyser(exampleOrgChart)
+	original := append(model.OrganisationChart{}, exampleOrgChart...)
+
+	chart, err := analyser.Reorganise(parseTestReorgScript(t, `remove "Gonzo the Great"
+move "Hit Girl" under "Black Widow"
+rename "Super Ted" to "Spotty"`))
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	expected := model.OrganisationChart{
+		model.Employee{Id: 1, Name: "Dangermouse"},
+		model.Employee{Id: 3, Name: "Invisible Woman", ManagerId: 1},
+		model.Employee{Id: 6, Name: "Black Widow", ManagerId: 1},
+		model.Employee{Id: 12, Name: "Hit Girl", ManagerId: 6},
+		model.Employee{Id: 15, Name: "Spotty", ManagerId: 3},
+		model.Employee{Id: 16, Name: "Batman", ManagerId: 6},
+		model.Employee{Id: 17, Name: "Catwoman", ManagerId: 6},
+	}
+
+	if !reflect.DeepEqual(chart, expected) {
+		t.Errorf("The chart %+v was not equal to the expected chart %+v", chart, expected)
+	}
+
+	if !reflect.DeepEqual(analyser.chart, original) {
+		t.Errorf("The analyser's chart was changed by the reorg.")
+	}
+}
+
+func TestRenamingAVacancyFillsIt(t *testing.T) {
+	analyser, _ := setupTestAnalyser(model.OrganisationChart{
+		model.Employee{Id: 1, Name: "Nick Fury"},
+		model.Employee{Id: 2, Name: "Head of Ops", ManagerId: 1, Vacant: true},
+	})
+
+	chart, err := analyser.Reorganise(parseTestReorgScript(t, `rename "Head of Ops" to "Maria Hill"`))
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	expected := model.Employee{Id: 2, Name: "Maria Hill", ManagerId: 1}
+
+	if !reflect.DeepEqual(chart[1], expected) {
+		t.Errorf("The employee %+v was not equal to the expected employee %+v", chart[1], expected)
+	}
+}
+
+func TestReorgErrorsOnlyGiveALineForScripts(t *testing.T) {
+	type testCase struct {
+		operation      ReorgOperation
+		expectedPrefix string
+		expectedLines  int // how many times "Line" appears
+	}
+
+	testCases := map[string]testCase{
+		"from a script":    {operation: ReorgOperation{Kind: RenameEmployee, Subject: "Nick Fury", Line: 4}, expectedPrefix: "Line 4 of the reorg script: ", expectedLines: 1},
+		"without a script": {operation: ReorgOperation{Kind: RenameEmployee, Subject: "Nick Fury"}, expectedPrefix: errAnalysisInvalidReorgOperation.Error(), expectedLines: 0},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			analyser, _ := setupTestAnalyser(duplicateNameOrgChart)
+			_, err := analyser.Reorganise([]ReorgOperation{tc.operation})
+
+			if err == nil || !strings.HasPrefix(err.Error(), tc.expectedPrefix) || strings.Count(err.Error(), "Line") != tc.expectedLines {
+				t.Errorf("The error '%v' should have started with '%s', and mentioned a line %d times.", err, tc.expectedPrefix, tc.expectedLines)
+			}
+		})
+	}
+}
+
+func TestAnalysingAReorg(t *testing.T) {
+	analyser, tw := setupTestAnalyser(exampleOrgChart)
+	operations := parseTestReorgScript(t, `move "Black Widow" under "Invisible Woman"
+remove "Gonzo the Great"
+add vacancy "Head of Ops" under #1
+add "Mr Tickle" under title="Head of Ops"`)
+
+	if err := analyser.AnalyseReorg(operations); err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	expected := `Applied 4 operations.
+
+Hires (2):
+  Vacancy (18), reporting to Dangermouse (1)
+  Mr Tickle (19), reporting to Vacancy (18)
+
+Departures (1):
+  Gonzo the Great (2), who reported to Dangermouse (1)
+
+Subtree relocations (1):
+  Black Widow (6) and 2 reports: Gonzo the Great (2) -> Invisible Woman (3)
+
+By manager (4):
+  Dangermouse (1): 1 hires, 1 departures, 0 moved in, 0 moved out
+  Gonzo the Great (2): 0 hires, 0 departures, 0 moved in, 1 moved out
+  Invisible Woman (3): 0 hires, 0 departures, 1 moved in, 0 moved out
+  Vacancy (18): 1 hires, 0 departures, 0 moved in, 0 moved out
+
+Stats:
+  Headcount: 8 (no change)
+  Vacancies: 0 -> 1 (+1)
+  Roots: 1 (no change)
+  Hierarchy depth: 4 (no change)
+  Widest level size: 3 -> 4 (+1)
+  Average distance to root: 1.75 -> 1.78 (+0.03)
+  Leaf ratio: 0.50 -> 0.56 (+0.06)
+  Managers: 4 (no change)
+  Median span of control: 2.0 (no change)
+  Max span of control: 2 -> 3 (+1)
+
+Changed chains of command:
+  Black Widow (6)
+    before: Black Widow (6) -> Gonzo the Great (2) -> Dangermouse (1)
+    after:  Black Widow (6) -> Invisible Woman (3) -> Dangermouse (1)`
+
+	if tw.contents != expected {
+		t.Errorf("The received output '%s' was not equal to the expected output '%s'", tw.contents, expected)
+	}
+}
+
+func TestAnalysingInvalidReorgsErrors(t *testing.T) {
+	type testCase struct {
+		script        string
+		expectedError error
+	}
+
+	testCases := map[string]testCase{
+		"with an unknown employee": {
+			script:        `remove "Mr Tickle"`,
+			expectedError: errAnalysisUnknownName,
+		},
+		"with an ambiguous selector": {
+			script:        `move name=Hawkeye under "Nick Fury"`,
+			expectedError: errAnalysisAmbiguousName,
+		},
+		"with a rename to a blank name": {
+			script:        `rename "Nick Fury" to ""`,
+			expectedError: errAnalysisInvalidReorgOperation,
+		},
+		"with a move that creates a cycle": {
+			script:        `move "Nick Fury" under #16`,
+			expectedError: errAnalysisInvalidReorganisation,
+		},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			analyser, tw := setupTestAnalyser(duplicateNameOrgChart)
+			err := analyser.AnalyseReorg(parseTestReorgScript(t, tc.script))
+
+			if !errors.Is(err, tc.expectedError) {
+				t.Errorf("The error '%v' was returned, but it was not the expected error '%v'", err, tc.expectedError)
+			}
+
+			if tw.contents != "" {
+				t.Errorf("The output '%s' was written for an invalid reorg.", tw.contents)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+var (
+	errAnalysisInvalidDepth = errors.New("The depth provided must be zero (unlimited) or a positive number of levels.")
+)
+
+// All the reports found at a particular distance below a manager.
+// Level 1 holds direct reports, level 2 their reports, and so on.
+type ReportLevel struct {
+	Level     int              `json:"level"`
+	Employees []model.Employee `json:"employees"`
+}
+
+// Writes the reports beneath every employee with the given name, grouped by level with a count for each.
+// A depth of 0 means there is no limit on how far down the chart to go.
+func (a *organisationChartAnalyser) AnalyseReports(name string, depth int) error {
+	ids, err := a.resolveSelector(name, errAnalysisUnknownName)
+
+	if err != nil {
+		return err
+	}
+
+	blocks := make([]string, 0, len(ids))
+
+	for _, id := range ids {
+		levels, err := a.Reports(id, depth)
+
+		if err != nil {
+			return err
+		}
+
+		blocks = append(blocks, a.reportsToString(a.employeeMap[id], levels))
+	}
+
+	_, err = a.output.Write([]byte(strings.Join(blocks, "\n\n")))
+
+	return err
+}
+
+// Returns the employees who report directly to the given employee.
+func (a *organisationChartAnalyser) DirectReports(id int) ([]model.Employee, error) {
+	levels, err := a.Reports(id, 1)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(levels) == 0 {
+		return []model.Employee{}, nil
+	}
+
+	return levels[0].Employees, nil
+}
+
+// Returns every transitive report of an employee, grouped by level.
+// This is a BFS over the directed manager > report index, so each level is fully discovered before the next.
+func (a *organisationChartAnalyser) Reports(id int, depth int) ([]ReportLevel, error) {
+	if depth < 0 {
+		return nil, errAnalysisInvalidDepth
+	}
+
+	if _, ok := a.employeeMap[id]; !ok {
+		return nil, errAnalysisUnknownEmployee
+	}
+
+	levels := make([]ReportLevel, 0)
+	current := []int{id}
+
+	// A cycle in the data would otherwise keep producing the same reports forever.
+	seenIds := map[int]bool{id: true}
+
+	for level := 1; len(current) > 0 && (depth == 0 || level <= depth); level++ {
+		next := make([]int, 0)
+		employees := make([]model.Employee, 0)
+
+		for _, managerId := range current {
+			for _, reportId := range a.reportsMap[managerId] {
+				if seenIds[reportId] {
+					continue
+				}
+
+				seenIds[reportId] = true
+				next = append(next, reportId)
+				employees = append(employees, a.employeeMap[reportId])
+			}
+		}
+
+		if len(employees) > 0 {
+			levels = append(levels, ReportLevel{Level: level, Employees: employees})
+		}
+
+		current = next
+	}
+
+	return levels, nil
+}
+
+func (a *organisationChartAnalyser) reportsToString(manager model.Employee, levels []ReportLevel) string {
+	var builder strings.Builder
+
+	builder.WriteString(labelEmployee(manager))
+
+	total := 0
+
+	for _, level := range levels {
+		names := make([]string, 0, len(level.Employees))
+
+		for _, employee := range level.Employees {
+			names = append(names, labelEmployee(employee))
+		}
+
+		total += len(level.Employees)
+		builder.WriteString(fmt.Sprintf("\nLevel %d (%d): %s", level.Level, len(level.Employees), strings.Join(names, ", ")))
+	}
+
+	builder.WriteString(fmt.Sprintf("\nTotal reports: %d", total))
+
+	return builder.String()
+}
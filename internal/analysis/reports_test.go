@@ -0,0 +1,142 @@
+package analysis
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+func reportLevelIds(levels []ReportLevel) [][]int {
+	ids := make([][]int, 0, len(levels))
+
+	for _, level := range levels {
+		levelIds := make([]int, 0, len(level.Employees))
+		for _, employee := range level.Employees {
+			levelIds = append(levelIds, employee.Id)
+		}
+		ids = append(ids, levelIds)
+	}
+
+	return ids
+}
+
+func TestReportsAreGroupedByLevel(t *testing.T) {
+	type testCase struct {
+		employeeId  int
+		depth       int
+		expectedIds [][]int
+	}
+
+	testCases := map[string]testCase{
+		"all reports of the root": {
+			employeeId:  1,
+			depth:       0,
+			expectedIds: [][]int{{2, 3}, {6, 12, 15}, {16, 17}},
+		},
+		"limited to two levels": {
+			employeeId:  1,
+			depth:       2,
+			expectedIds: [][]int{{2, 3}, {6, 12, 15}},
+		},
+		"direct reports only": {
+			employeeId:  3,
+			depth:       1,
+			expectedIds: [][]int{{12, 15}},
+		},
+		"an employee without reports": {
+			employeeId:  16,
+			depth:       0,
+			expectedIds: [][]int{},
+		},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			analyser, _ := setupTestAnalyser(exampleOrgChart)
+			levels, err := analyser.Reports(tc.employeeId, tc.depth)
+
+			if err != nil {
+				t.Fatalf("There was an error '%s' finding reports.", err)
+			}
+
+			ids := reportLevelIds(levels)
+
+			if !slices.EqualFunc(ids, tc.expectedIds, slices.Equal) {
+				t.Errorf("The reports %v were not equal to the expected reports %v", ids, tc.expectedIds)
+			}
+
+			for i, level := range levels {
+				if level.Level != i+1 {
+					t.Errorf("Level %d was numbered %d.", i+1, level.Level)
+				}
+			}
+		})
+	}
+}
+
+func TestDirectReports(t *testing.T) {
+	analyser, _ := setupTestAnalyser(exampleOrgChart)
+	reports, err := analyser.DirectReports(6)
+
+	if err != nil {
+		t.Fatalf("There was an error '%s' finding direct reports.", err)
+	}
+
+	expected := []model.Employee{exampleOrgChart[6], exampleOrgChart[7]}
+
+	if !reflect.DeepEqual(reports, expected) {
+		t.Errorf("The direct reports %v were not equal to the expected reports %v", reports, expected)
+	}
+}
+
+func TestReportsStopsOnManagementCycles(t *testing.T) {
+	analyser, _ := setupTestAnalyser(model.OrganisationChart{
+		model.Employee{Id: 1, Name: "CEO", ManagerId: 3},
+		model.Employee{Id: 2, Name: "VP", ManagerId: 1},
+		model.Employee{Id: 3, Name: "SWE", ManagerId: 2},
+	})
+
+	levels, err := analyser.Reports(1, 0)
+
+	if err != nil {
+		t.Fatalf("There was an error '%s' finding reports.", err)
+	}
+
+	if ids := reportLevelIds(levels); !slices.EqualFunc(ids, [][]int{{2}, {3}}, slices.Equal) {
+		t.Errorf("The reports %v did not stop at the cycle.", ids)
+	}
+}
+
+func TestAnalysisReturnsReportsAsString(t *testing.T) {
+	analyser, writer := setupTestAnalyser(exampleOrgChart)
+	err := analyser.AnalyseReports("Gonzo the Great", 0)
+
+	if err != nil {
+		t.Fatalf("There was an error '%s' analysing the given input.", err)
+	}
+
+	expectedOutput := "Gonzo the Great (2)\nLevel 1 (1): Black Widow (6)\nLevel 2 (2): Batman (16), Catwoman (17)\nTotal reports: 3"
+
+	if writer.contents != expectedOutput {
+		t.Errorf("The received output '%s' was not equal to the expected output '%s'", writer.contents, expectedOutput)
+	}
+}
+
+func TestReportsErrorsWithInvalidArguments(t *testing.T) {
+	analyser, _ := setupTestAnalyser(exampleOrgChart)
+
+	if _, err := analyser.Reports(1, -1); err != errAnalysisInvalidDepth {
+		t.Errorf("The error '%v' was not the expected error '%v'", err, errAnalysisInvalidDepth)
+	}
+
+	if _, err := analyser.Reports(99, 0); err != errAnalysisUnknownEmployee {
+		t.Errorf("The error '%v' was not the expected error '%v'", err, errAnalysisUnknownEmployee)
+	}
+
+	if err := analyser.AnalyseReports("Superman", 0); !errors.Is(err, errAnalysisUnknownName) {
+		t.Errorf("The error '%v' was not the expected error '%v'", err, errAnalysisUnknownName)
+	}
+}
@@ -0,0 +1,219 @@
+package analysis
+
+import (
+	"slices"
+	"sort"
+)
+
+// Employees and relationships a search isn't allowed to use.
+// Edges are directional, keyed as [from, to].
+type searchExclusions struct {
+	ids   map[int]bool
+	edges map[[2]int]bool
+}
+
+func (e searchExclusions) allows(from int, to int) bool {
+	return !e.ids[to] && !e.edges[[2]int{from, to}]
+}
+
+// Works out which paths to return for a single start and target, depending on the search mode.
+// Avoided employees are excluded from every mode. Required employees always give a single path, as they're routed leg by leg -
+// resolveConstraints turns down asking for more than one alongside them.
+func (a *organisationChartAnalyser) pathsBetween(startId int, targetId int, constraints pathConstraints) ([][]int, error) {
+	if constraints.avoided[startId] || constraints.avoided[targetId] {
+		return nil, errAnalysisNoPathsFound
+	}
+
+	excluded := searchExclusions{ids: constraints.avoided}
+
+	switch {
+	case len(constraints.required) > 0:
+		path, err := a.routeThrough(startId, targetId, constraints.required, excluded)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return [][]int{path}, nil
+	case a.kShortestPaths > 0:
+		return a.kShortestSimplePaths(startId, targetId, a.kShortestPaths, excluded)
+	case a.allShortestPaths && a.costRules != nil:
+		return a.constructAllPaths(startId, targetId, a.weightedSearch(startId, targetId, excluded))
+	case a.allShortestPaths:
+		return a.constructAllPaths(startId, targetId, a.searchAll(startId, targetId, excluded))
+	default:
+		path, err := a.shortestPath(startId, targetId, excluded)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return [][]int{path}, nil
+	}
+}
+
+// The building block for every search mode - BFS by default, bidirectional BFS if chosen, or Dijkstra when there are cost rules.
+func (a *organisationChartAnalyser) shortestPath(startId int, targetId int, excluded searchExclusions) ([]int, error) {
+	if a.costRules != nil {
+		return a.constructPath(startId, targetId, firstPathIds(a.weightedSearch(startId, targetId, excluded)))
+	}
+
+	if a.searchStrategy == BidirectionalSearch {
+		return a.constructPath(startId, targetId, a.bidirectionalSearch(startId, targetId, excluded))
+	}
+
+	return a.constructPath(startId, targetId, a.search(startId, targetId, excluded))
+}
+
+// Reduces a multi-parent map down to the single previous hop per node that constructPath expects.
+func firstPathIds(pathMap map[int][]int) map[int]int {
+	pathIds := make(map[int]int, len(pathMap))
+
+	for id, prev := range pathMap {
+		pathIds[id] = prev[0]
+	}
+
+	return pathIds
+}
+
+// A variation on the BFS in search - instead of one previous hop per node, we keep every previous hop that's on a shortest path.
+// To do that, the whole level containing the target has to be finished before stopping.
+func (a *organisationChartAnalyser) searchAll(startId int, targetId int, excluded searchExclusions) map[int][]int {
+	depths := map[int]int{startId: 0}
+	pathIds := make(map[int][]int)
+	queue := []int{startId}
+
+	for len(queue) > 0 && !a.cancelled() {
+		currentId := queue[0]
+		queue = queue[1:]
+
+		// Everything on this level or deeper is further away than the target, so there's nothing left to find.
+		if targetDepth, found := depths[targetId]; found && depths[currentId] >= targetDepth {
+			break
+		}
+
+		for _, relationId := range a.adjList[currentId] {
+			if !excluded.allows(currentId, relationId) {
+				continue
+			}
+
+			depth, seen := depths[relationId]
+
+			if !seen {
+				depths[relationId] = depths[currentId] + 1
+				pathIds[relationId] = []int{currentId}
+				queue = append(queue, relationId)
+				continue
+			}
+
+			// Another route of the same length into a node we've already reached.
+			if depth == depths[currentId]+1 {
+				pathIds[relationId] = append(pathIds[relationId], currentId)
+			}
+		}
+	}
+
+	return pathIds
+}
+
+// The multi-parent version of constructPath - walks back from the target, branching wherever there was more than one previous hop.
+// With free hops, two employees can each be the other's previous hop, so a walk never goes back through one it's already been through.
+func (a *organisationChartAnalyser) constructAllPaths(startId int, targetId int, pathMap map[int][]int) ([][]int, error) {
+	if startId == targetId {
+		return [][]int{{startId}}, nil
+	}
+
+	if _, ok := pathMap[targetId]; !ok {
+		return nil, errAnalysisNoPathsFound
+	}
+
+	paths := make([][]int, 0)
+
+	var walk func(currentId int, suffix []int)
+	walk = func(currentId int, suffix []int) {
+		suffix = append([]int{currentId}, suffix...)
+
+		if currentId == startId {
+			paths = append(paths, suffix)
+			return
+		}
+
+		for _, prev := range pathMap[currentId] {
+			if !slices.Contains(suffix, prev) {
+				walk(prev, suffix)
+			}
+		}
+	}
+
+	walk(targetId, []int{})
+
+	return paths, nil
+}
+
+// Yen's algorithm for the k shortest simple paths.
+// Each new path is found by taking a prefix (the root) of one we already have, and searching for a different route (the spur)
+// from the end of that prefix to the target, with the edges used by existing paths sharing that root removed.
+func (a *organisationChartAnalyser) kShortestSimplePaths(startId int, targetId int, k int, base searchExclusions) ([][]int, error) {
+	first, err := a.shortestPath(startId, targetId, base)
+
+	if err != nil {
+		return nil, err
+	}
+
+	found := [][]int{first}
+	candidates := make([][]int, 0)
+
+	for len(found) < k && !a.cancelled() {
+		previous := found[len(found)-1]
+
+		for i := 0; i < len(previous)-1; i++ {
+			spurId := previous[i]
+			root := previous[:i+1]
+
+			excluded := excludingIds(base)
+			excluded.edges = make(map[[2]int]bool)
+
+			for _, path := range found {
+				if len(path) > i+1 && slices.Equal(path[:i+1], root) {
+					excluded.edges[[2]int{path[i], path[i+1]}] = true
+				}
+			}
+
+			// The spur can't wander back through the root, or the path wouldn't be simple.
+			for _, id := range root[:i] {
+				excluded.ids[id] = true
+			}
+
+			spur, err := a.shortestPath(spurId, targetId, excluded)
+
+			if err != nil {
+				continue
+			}
+
+			candidate := append(slices.Clone(root[:i]), spur...)
+
+			if !containsPath(found, candidate) && !containsPath(candidates, candidate) {
+				candidates = append(candidates, candidate)
+			}
+		}
+
+		if len(candidates) == 0 {
+			break
+		}
+
+		sort.SliceStable(candidates, func(i int, j int) bool {
+			return a.pathCost(candidates[i]) < a.pathCost(candidates[j])
+		})
+
+		found = append(found, candidates[0])
+		candidates = candidates[1:]
+	}
+
+	return found, nil
+}
+
+func containsPath(paths [][]int, path []int) bool {
+	return slices.ContainsFunc(paths, func(p []int) bool {
+		return slices.Equal(p, path)
+	})
+}
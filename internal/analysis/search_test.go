@@ -0,0 +1,122 @@
+package analysis
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+// With solid lines only, a management cycle is the only way to get more than one route.
+var squareOrgChart = model.OrganisationChart{
+	model.Employee{Id: 1, Name: "A", ManagerId: 4},
+	model.Employee{Id: 2, Name: "B", ManagerId: 1},
+	model.Employee{Id: 3, Name: "C", ManagerId: 2},
+	model.Employee{Id: 4, Name: "D", ManagerId: 3},
+	model.Employee{Id: 5, Name: "E", ManagerId: 3},
+}
+
+var pentagonOrgChart = model.OrganisationChart{
+	model.Employee{Id: 1, Name: "A", ManagerId: 5},
+	model.Employee{Id: 2, Name: "B", ManagerId: 1},
+	model.Employee{Id: 3, Name: "C", ManagerId: 2},
+	model.Employee{Id: 4, Name: "D", ManagerId: 3},
+	model.Employee{Id: 5, Name: "E", ManagerId: 4},
+}
+
+func resultIds(results []PathResult) [][]int {
+	ids := make([][]int, 0, len(results))
+	for _, result := range results {
+		ids = append(ids, result.Ids)
+	}
+	return ids
+}
+
+func TestPathSearchModes(t *testing.T) {
+	type testCase struct {
+		input         model.OrganisationChart
+		options       []AnalyserOption
+		employee1     string
+		employee2     string
+		expectedPaths [][]int
+	}
+
+	testCases := map[string]testCase{
+		"single shortest path by default": {
+			input:         squareOrgChart,
+			employee1:     "A",
+			employee2:     "E",
+			expectedPaths: [][]int{{1, 4, 3, 5}},
+		},
+		"all equal length shortest paths": {
+			input:         squareOrgChart,
+			options:       []AnalyserOption{WithAllShortestPaths()},
+			employee1:     "A",
+			employee2:     "E",
+			expectedPaths: [][]int{{1, 4, 3, 5}, {1, 2, 3, 5}},
+		},
+		"all shortest paths when there is only one": {
+			input:         pentagonOrgChart,
+			options:       []AnalyserOption{WithAllShortestPaths()},
+			employee1:     "A",
+			employee2:     "C",
+			expectedPaths: [][]int{{1, 2, 3}},
+		},
+		"k shortest paths in order of length": {
+			input:         pentagonOrgChart,
+			options:       []AnalyserOption{WithKShortestPaths(2)},
+			employee1:     "A",
+			employee2:     "C",
+			expectedPaths: [][]int{{1, 2, 3}, {1, 5, 4, 3}},
+		},
+		"k larger than the number of simple paths": {
+			input:         pentagonOrgChart,
+			options:       []AnalyserOption{WithKShortestPaths(5)},
+			employee1:     "A",
+			employee2:     "C",
+			expectedPaths: [][]int{{1, 2, 3}, {1, 5, 4, 3}},
+		},
+		"k shortest paths in a tree": {
+			input:         exampleOrgChart,
+			options:       []AnalyserOption{WithKShortestPaths(3)},
+			employee1:     "Batman",
+			employee2:     "Catwoman",
+			expectedPaths: [][]int{{16, 6, 17}},
+		},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			analyser, _ := setupTestAnalyser(tc.input, tc.options...)
+			results, err := analyser.Paths(tc.employee1, tc.employee2)
+
+			if err != nil {
+				t.Fatalf("There was an error '%s' finding paths.", err)
+			}
+
+			if ids := resultIds(results); !slices.EqualFunc(ids, tc.expectedPaths, slices.Equal) {
+				t.Errorf("The paths %v were not equal to the expected paths %v", ids, tc.expectedPaths)
+			}
+		})
+	}
+}
+
+func TestSearchHonoursExclusions(t *testing.T) {
+	analyser, _ := setupTestAnalyser(pentagonOrgChart)
+
+	path, err := analyser.shortestPath(1, 3, searchExclusions{ids: map[int]bool{2: true}})
+
+	if err != nil {
+		t.Fatalf("There was an error '%s' finding a path.", err)
+	}
+
+	if !slices.Equal(path, []int{1, 5, 4, 3}) {
+		t.Errorf("The path %v went through an excluded employee.", path)
+	}
+
+	_, err = analyser.shortestPath(1, 3, searchExclusions{ids: map[int]bool{2: true, 4: true}})
+
+	if err != errAnalysisNoPathsFound {
+		t.Errorf("The error '%v' was not the expected error '%v'", err, errAnalysisNoPathsFound)
+	}
+}
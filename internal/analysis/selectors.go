@@ -0,0 +1,189 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+var (
+	errAnalysisAmbiguousName = errors.New("The selector provided matches more than one employee - use an ID (#17) or a qualified selector (name=...,department=...) to choose one.")
+)
+
+// Keys that can be used in a qualified selector such as "name=Hawkeye,department=Ops".
+var selectorKeys = []string{"id", "name", "title", "department"}
+
+// Employees can be selected in three ways:
+// - "#17" selects the employee with ID 17
+// - "name=Hawkeye,department=Ops" selects employees matching every key, using the same normalisation as names
+// - anything else is treated as a name
+func (a *organisationChartAnalyser) resolveSelector(selector string, notFound error) ([]int, error) {
+	ids, err := a.matchSelector(selector, notFound)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// In strict mode we'd rather stop and ask than silently pick between people with the same name.
+	if a.strictNames && len(ids) > 1 {
+		return nil, fmt.Errorf("%w '%s' matches %s.", errAnalysisAmbiguousName, selector, a.describeCandidates(ids))
+	}
+
+	return ids, nil
+}
+
+func (a *organisationChartAnalyser) matchSelector(selector string, notFound error) ([]int, error) {
+	trimmed := strings.TrimSpace(selector)
+
+	if idString, ok := strings.CutPrefix(trimmed, "#"); ok {
+		if id, err := strconv.Atoi(idString); err == nil {
+			if _, ok := a.employeeMap[id]; !ok {
+				return nil, fmt.Errorf("%w No employee has the ID %d.", notFound, id)
+			}
+
+			return []int{id}, nil
+		}
+	}
+
+	if criteria, ok := parseQualifiedSelector(trimmed); ok {
+		ids := make([]int, 0)
+
+		for _, employee := range a.chart {
+			if employeeMatches(employee, criteria) {
+				ids = append(ids, employee.Id)
+			}
+		}
+
+		if len(ids) == 0 {
+			return nil, fmt.Errorf("%w '%s' does not match any employee.", notFound, selector)
+		}
+
+		return ids, nil
+	}
+
+	return a.resolveName(selector, notFound)
+}
+
+// Only treat the input as a qualified selector if every part is a known key=value pair.
+// Otherwise a name that happens to contain "=" or "," would become impossible to look up.
+func parseQualifiedSelector(selector string) (map[string]string, bool) {
+	if !strings.Contains(selector, "=") {
+		return nil, false
+	}
+
+	criteria := make(map[string]string)
+
+	for _, part := range strings.Split(selector, ",") {
+		key, value, ok := strings.Cut(part, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+
+		if !ok || !slices.Contains(selectorKeys, key) {
+			return nil, false
+		}
+
+		criteria[key] = normaliseName(value)
+	}
+
+	return criteria, true
+}
+
+func employeeMatches(employee model.Employee, criteria map[string]string) bool {
+	for key, value := range criteria {
+		var field string
+
+		switch key {
+		case "id":
+			field = strconv.Itoa(employee.Id)
+		case "name":
+			field = employee.Name
+		case "title":
+			field = employee.Title
+		case "department":
+			field = employee.Department
+		}
+
+		if normaliseName(field) != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (a *organisationChartAnalyser) describeCandidates(ids []int) string {
+	candidates := make([]string, 0, len(ids))
+
+	for _, id := range ids {
+		candidates = append(candidates, describeEmployee(a.employeeMap[id]))
+	}
+
+	return strings.Join(candidates, ", ")
+}
+
+// Everything we know that might tell two employees apart - the ID, plus the title and department where the chart has them.
+func describeEmployee(employee model.Employee) string {
+	details := []string{strconv.Itoa(employee.Id)}
+
+	for _, detail := range []string{employee.Title, employee.Department} {
+		if detail != "" {
+			details = append(details, detail)
+		}
+	}
+
+	if employee.Vacant {
+		details = append(details, "vacant")
+	}
+
+	return fmt.Sprintf("%s (%s)", employee.Name, strings.Join(details, ", "))
+}
+
+// The employees a selector picks out. Follows the name matching options, so with strict names an ambiguous selector is an error.
+func (a *organisationChartAnalyser) Find(selector string) ([]model.Employee, error) {
+	ids, err := a.resolveSelector(selector, errAnalysisUnknownName)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return a.employeesById(ids), nil
+}
+
+// Like Find, but the selector has to pick out exactly one employee.
+func (a *organisationChartAnalyser) FindOne(selector string) (model.Employee, error) {
+	id, err := a.resolveOne(selector)
+
+	if err != nil {
+		return model.Employee{}, err
+	}
+
+	return a.employeeMap[id], nil
+}
+
+// Employees for a free text query - whoever the query selects if anyone, otherwise the closest names, best first.
+func (a *organisationChartAnalyser) Search(query string) []model.Employee {
+	if ids, err := a.matchSelector(query, errAnalysisUnknownName); err == nil {
+		return a.employeesById(ids)
+	}
+
+	employees := make([]model.Employee, 0)
+
+	for _, suggestion := range a.SuggestNames(query) {
+		employees = append(employees, a.employeesById(suggestion.Ids)...)
+	}
+
+	return employees
+}
+
+func (a *organisationChartAnalyser) employeesById(ids []int) []model.Employee {
+	employees := make([]model.Employee, 0, len(ids))
+
+	for _, id := range ids {
+		employees = append(employees, a.employeeMap[id])
+	}
+
+	return employees
+}
@@ -0,0 +1,124 @@
+package analysis
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+var duplicateNameOrgChart = model.OrganisationChart{
+	model.Employee{Id: 1, Name: "Nick Fury", Title: "Director"},
+	model.Employee{Id: 6, Name: "Black Widow", ManagerId: 1, Title: "Lead", Department: "Ops"},
+	model.Employee{Id: 7, Name: "Maria Hill", ManagerId: 1, Title: "Lead", Department: "Intel"},
+	model.Employee{Id: 16, Name: "Hawkeye", ManagerId: 6, Title: "Archer", Department: "Ops"},
+	model.Employee{Id: 40, Name: "Hawkeye", ManagerId: 7, Title: "Analyst", Department: "Intel"},
+}
+
+func TestResolvingSelectors(t *testing.T) {
+	type testCase struct {
+		selector    string
+		expectedIds []int
+	}
+
+	testCases := map[string]testCase{
+		"by name":                    {selector: "Hawkeye", expectedIds: []int{16, 40}},
+		"by ID":                      {selector: "#40", expectedIds: []int{40}},
+		"by qualified selector":      {selector: "name=Hawkeye,department=Ops", expectedIds: []int{16}},
+		"by title alone":             {selector: "title=lead", expectedIds: []int{6, 7}},
+		"with whitespace and casing": {selector: " Name = hawkeye , Title = ANALYST ", expectedIds: []int{40}},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			analyser, _ := setupTestAnalyser(duplicateNameOrgChart)
+			ids, err := analyser.resolveSelector(tc.selector, errAnalysisUnknownName)
+
+			if err != nil {
+				t.Fatalf("There was an error '%s' resolving the selector.", err)
+			}
+
+			if !slices.Equal(ids, tc.expectedIds) {
+				t.Errorf("The IDs %v were not equal to the expected IDs %v", ids, tc.expectedIds)
+			}
+		})
+	}
+}
+
+func TestResolvingInvalidSelectorsErrors(t *testing.T) {
+	testCases := map[string]string{
+		"with an unknown ID":                   "#99",
+		"with a qualified selector that fails": "name=Hawkeye,department=Finance",
+		"with an unknown name":                 "Thanos",
+	}
+
+	for desc, selector := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			analyser, _ := setupTestAnalyser(duplicateNameOrgChart)
+			_, err := analyser.resolveSelector(selector, errAnalysisUnknownName)
+
+			if !errors.Is(err, errAnalysisUnknownName) {
+				t.Errorf("The error '%v' was not the expected error '%v'", err, errAnalysisUnknownName)
+			}
+		})
+	}
+}
+
+func TestAnalysisAcceptsSelectors(t *testing.T) {
+	analyser, writer := setupTestAnalyser(duplicateNameOrgChart)
+
+	if err := analyser.Analyse("#40", "name=Hawkeye,department=Ops"); err != nil {
+		t.Fatalf("There was an error '%s' analysing the given input.", err)
+	}
+
+	expectedOutput := "Hawkeye (40) -> Maria Hill (7) -> Nick Fury (1) <- Black Widow (6) <- Hawkeye (16)"
+
+	if writer.contents != expectedOutput {
+		t.Errorf("The received output '%s' was not equal to the expected output '%s'", writer.contents, expectedOutput)
+	}
+}
+
+func TestStrictNamesErrorsWithCandidates(t *testing.T) {
+	analyser := NewOrganisationChartAnalyser(&testWriter{}, duplicateNameOrgChart, WithStrictNames())
+	err := analyser.Analyse("Hawkeye", "Nick Fury")
+
+	if !errors.Is(err, errAnalysisAmbiguousName) {
+		t.Fatalf("The error '%v' was not the expected error '%v'", err, errAnalysisAmbiguousName)
+	}
+
+	if !strings.Contains(err.Error(), "Hawkeye (16, Archer, Ops), Hawkeye (40, Analyst, Intel)") {
+		t.Errorf("The error '%s' did not list the candidates.", err)
+	}
+}
+
+func TestFindingAndSearchingForEmployees(t *testing.T) {
+	analyser, _ := setupTestAnalyser(duplicateNameOrgChart)
+
+	if employees, err := analyser.Find("Hawkeye"); err != nil || len(employees) != 2 {
+		t.Errorf("Finding Hawkeye returned %v and the error '%v', expected both Hawkeyes.", employees, err)
+	}
+
+	if _, err := analyser.FindOne("Hawkeye"); !errors.Is(err, errAnalysisAmbiguousName) || !IsInvalidRequest(err) {
+		t.Errorf("The error '%v' was returned, but it was not the expected error '%v'", err, errAnalysisAmbiguousName)
+	}
+
+	if employee, err := analyser.FindOne("name=Hawkeye,department=Intel"); err != nil || employee.Id != 40 {
+		t.Errorf("Finding one Hawkeye returned %v and the error '%v', expected the Hawkeye in Intel.", employee, err)
+	}
+
+	if _, err := analyser.FindOne("Thor"); !IsNotFound(err) {
+		t.Errorf("The error '%v' returned for an unknown name wasn't a not found error.", err)
+	}
+
+	ids := make([]int, 0)
+
+	for _, employee := range analyser.Search("Maria Hil") {
+		ids = append(ids, employee.Id)
+	}
+
+	if !slices.Equal(ids, []int{7}) {
+		t.Errorf("The search returned %v, expected the close match %v", ids, []int{7})
+	}
+}
@@ -0,0 +1,273 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+// Health metrics for a whole chart.
+// Levels are counted from the top of the chart, so roots are at level 0.
+type ChartStats struct {
+	Headcount             int           `json:"headcount"` // people - vacant positions don't count
+	Positions             int           `json:"positions"` // every row in the chart, filled or not
+	Roots                 int           `json:"roots"`
+	BrokenChains          int           `json:"brokenChains"` // employees whose manager ID doesn't exist in the chart - they're treated as roots
+	Unreachable           int           `json:"unreachable"`  // employees that can't be reached from any root, which only happens with management cycles
+	Depth                 int           `json:"depth"`        // number of levels in the hierarchy
+	WidestLevel           int           `json:"widestLevel"`
+	WidestLevelSize       int           `json:"widestLevelSize"`
+	Leaves                int           `json:"leaves"`
+	LeafRatio             float64       `json:"leafRatio"`
+	AverageDistanceToRoot float64       `json:"averageDistanceToRoot"`
+	SpanOfControl         SpanOfControl `json:"spanOfControl"`
+	Vacancies             []Vacancy     `json:"vacancies"`
+}
+
+// An open position, and the people reporting to it who are left without anyone in post above them.
+type Vacancy struct {
+	Position        model.Employee   `json:"position"`
+	OrphanedReports []model.Employee `json:"orphanedReports"` // solid line reports only - vacancies reporting to a vacancy are listed as vacancies themselves
+}
+
+// Summary of how many direct reports each manager has.
+// Only employees with at least one report count as managers.
+type SpanOfControl struct {
+	Managers int           `json:"managers"`
+	Min      int           `json:"min"`
+	Median   float64       `json:"median"`
+	Max      int           `json:"max"`
+	Outliers []SpanOutlier `json:"outliers"`
+}
+
+type SpanOutlier struct {
+	Manager model.Employee `json:"manager"`
+	Span    int            `json:"span"`
+}
+
+// Writes the chart statistics in a human readable format.
+func (a *organisationChartAnalyser) AnalyseStats() error {
+	_, err := a.output.Write([]byte(a.statsToString(a.Stats())))
+
+	return err
+}
+
+func (a *organisationChartAnalyser) Stats() ChartStats {
+	stats := ChartStats{Positions: len(a.employeeMap), Vacancies: a.vacancies()}
+	stats.Headcount = stats.Positions - len(stats.Vacancies)
+
+	// Anyone without a manager in the chart is the top of their own tree.
+	roots := make([]int, 0)
+
+	for _, employee := range a.chart {
+		if employee.ManagerId == 0 {
+			roots = append(roots, employee.Id)
+			continue
+		}
+
+		if _, ok := a.employeeMap[employee.ManagerId]; !ok {
+			stats.BrokenChains++
+			roots = append(roots, employee.Id)
+		}
+	}
+
+	stats.Roots = len(roots)
+
+	// Walk down from the roots a level at a time, much like Reports does for a single employee.
+	levelSizes := make([]int, 0)
+	seenIds := make(map[int]bool)
+	totalDistance := 0
+	current := roots
+
+	for _, id := range roots {
+		seenIds[id] = true
+	}
+
+	for level := 0; len(current) > 0; level++ {
+		levelSizes = append(levelSizes, len(current))
+		totalDistance += level * len(current)
+
+		next := make([]int, 0)
+
+		for _, managerId := range current {
+			for _, reportId := range a.reportsMap[managerId] {
+				if !seenIds[reportId] {
+					seenIds[reportId] = true
+					next = append(next, reportId)
+				}
+			}
+		}
+
+		current = next
+	}
+
+	reachable := len(seenIds)
+	stats.Unreachable = stats.Positions - reachable
+	stats.Depth = len(levelSizes)
+
+	for level, size := range levelSizes {
+		if size > stats.WidestLevelSize {
+			stats.WidestLevel = level
+			stats.WidestLevelSize = size
+		}
+	}
+
+	if reachable > 0 {
+		stats.AverageDistanceToRoot = float64(totalDistance) / float64(reachable)
+	}
+
+	for id := range a.employeeMap {
+		if len(a.reportsMap[id]) == 0 {
+			stats.Leaves++
+		}
+	}
+
+	if stats.Positions > 0 {
+		stats.LeafRatio = float64(stats.Leaves) / float64(stats.Positions)
+	}
+
+	stats.SpanOfControl = a.spanOfControl()
+
+	return stats
+}
+
+func (a *organisationChartAnalyser) vacancies() []Vacancy {
+	vacancies := make([]Vacancy, 0)
+
+	for _, employee := range a.chart {
+		if !employee.Vacant {
+			continue
+		}
+
+		vacancy := Vacancy{Position: employee, OrphanedReports: []model.Employee{}}
+
+		for _, reportId := range a.reportsMap[employee.Id] {
+			if report := a.employeeMap[reportId]; !report.Vacant {
+				vacancy.OrphanedReports = append(vacancy.OrphanedReports, report)
+			}
+		}
+
+		vacancies = append(vacancies, vacancy)
+	}
+
+	return vacancies
+}
+
+func (a *organisationChartAnalyser) spanOfControl() SpanOfControl {
+	spans := make([]int, 0)
+
+	// Iterate over the chart rather than the map so outliers come out in a stable order.
+	for _, employee := range a.chart {
+		if span := len(a.reportsMap[employee.Id]); span > 0 {
+			spans = append(spans, span)
+		}
+	}
+
+	if len(spans) == 0 {
+		return SpanOfControl{Outliers: []SpanOutlier{}}
+	}
+
+	sorted := slices.Clone(spans)
+	slices.Sort(sorted)
+
+	span := SpanOfControl{
+		Managers: len(sorted),
+		Min:      sorted[0],
+		Median:   percentile(sorted, 0.5),
+		Max:      sorted[len(sorted)-1],
+		Outliers: []SpanOutlier{},
+	}
+
+	// Tukey's fences - anything more than 1.5 times the interquartile range outside the middle half is an outlier.
+	// Spans are small whole numbers, so the range is floored at 1 to stop a single report's difference counting as an outlier.
+	q1 := percentile(sorted, 0.25)
+	q3 := percentile(sorted, 0.75)
+	iqr := math.Max(q3-q1, 1)
+
+	for _, employee := range a.chart {
+		reports := len(a.reportsMap[employee.Id])
+
+		if reports == 0 {
+			continue
+		}
+
+		if float64(reports) < q1-1.5*iqr || float64(reports) > q3+1.5*iqr {
+			span.Outliers = append(span.Outliers, SpanOutlier{Manager: employee, Span: reports})
+		}
+	}
+
+	return span
+}
+
+// Linear interpolation between the closest ranks of an already sorted slice.
+func percentile(sorted []int, p float64) float64 {
+	position := p * float64(len(sorted)-1)
+	lower := int(math.Floor(position))
+	upper := int(math.Ceil(position))
+
+	return float64(sorted[lower]) + (position-float64(lower))*float64(sorted[upper]-sorted[lower])
+}
+
+func (a *organisationChartAnalyser) statsToString(stats ChartStats) string {
+	headcount := fmt.Sprintf("Headcount: %d", stats.Headcount)
+
+	if len(stats.Vacancies) > 0 {
+		headcount += fmt.Sprintf(" (%d positions, %d vacant)", stats.Positions, len(stats.Vacancies))
+	}
+
+	lines := []string{
+		headcount,
+		fmt.Sprintf("Roots: %d", stats.Roots),
+		fmt.Sprintf("Hierarchy depth: %d", stats.Depth),
+		fmt.Sprintf("Widest level: %d (%d employees)", stats.WidestLevel, stats.WidestLevelSize),
+		fmt.Sprintf("Average distance to root: %.2f", stats.AverageDistanceToRoot),
+		fmt.Sprintf("Leaf ratio: %.2f (%d of %d)", stats.LeafRatio, stats.Leaves, stats.Positions),
+		fmt.Sprintf("Span of control: min %d, median %.1f, max %d across %d managers", stats.SpanOfControl.Min, stats.SpanOfControl.Median, stats.SpanOfControl.Max, stats.SpanOfControl.Managers),
+	}
+
+	if len(stats.SpanOfControl.Outliers) == 0 {
+		lines = append(lines, "Span of control outliers: none")
+	} else {
+		outliers := make([]string, 0, len(stats.SpanOfControl.Outliers))
+		for _, outlier := range stats.SpanOfControl.Outliers {
+			outliers = append(outliers, fmt.Sprintf("%s (%d) with %d", outlier.Manager.Name, outlier.Manager.Id, outlier.Span))
+		}
+		lines = append(lines, "Span of control outliers: "+strings.Join(outliers, ", "))
+	}
+
+	if len(stats.Vacancies) > 0 {
+		vacancies := make([]string, 0, len(stats.Vacancies))
+		orphans := 0
+
+		for _, vacancy := range stats.Vacancies {
+			reports := make([]string, 0, len(vacancy.OrphanedReports))
+
+			for _, report := range vacancy.OrphanedReports {
+				reports = append(reports, labelEmployee(report))
+			}
+
+			orphans += len(reports)
+
+			if len(reports) == 0 {
+				vacancies = append(vacancies, fmt.Sprintf("%s (%d) with no reports", vacancy.Position.Name, vacancy.Position.Id))
+			} else {
+				vacancies = append(vacancies, fmt.Sprintf("%s (%d) with %d orphaned reports: %s", vacancy.Position.Name, vacancy.Position.Id, len(reports), strings.Join(reports, ", ")))
+			}
+		}
+
+		lines = append(lines, fmt.Sprintf("Vacancies: %d, with %d orphaned reports\n  %s", len(stats.Vacancies), orphans, strings.Join(vacancies, "\n  ")))
+	}
+
+	if stats.BrokenChains > 0 {
+		lines = append(lines, fmt.Sprintf("Broken chains: %d", stats.BrokenChains))
+	}
+
+	if stats.Unreachable > 0 {
+		lines = append(lines, fmt.Sprintf("Unreachable (management cycles): %d", stats.Unreachable))
+	}
+
+	return strings.Join(lines, "\n")
+}
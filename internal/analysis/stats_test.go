@@ -0,0 +1,134 @@
+package analysis
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+func TestStatsForExampleChart(t *testing.T) {
+	analyser, _ := setupTestAnalyser(exampleOrgChart)
+	stats := analyser.Stats()
+
+	expected := ChartStats{
+		Headcount:             8,
+		Positions:             8,
+		Roots:                 1,
+		Depth:                 4,
+		WidestLevel:           2,
+		WidestLevelSize:       3,
+		Leaves:                4,
+		LeafRatio:             0.5,
+		AverageDistanceToRoot: 1.75,
+		SpanOfControl: SpanOfControl{
+			Managers: 4,
+			Min:      1,
+			Median:   2,
+			Max:      2,
+			Outliers: []SpanOutlier{},
+		},
+		Vacancies: []Vacancy{},
+	}
+
+	if !reflect.DeepEqual(stats, expected) {
+		t.Errorf("The stats %+v were not equal to the expected stats %+v", stats, expected)
+	}
+}
+
+func TestStatsFindsSpanOfControlOutliers(t *testing.T) {
+	chart := model.OrganisationChart{
+		model.Employee{Id: 1, Name: "CEO"},
+		model.Employee{Id: 2, Name: "VP One", ManagerId: 1},
+		model.Employee{Id: 3, Name: "VP Two", ManagerId: 1},
+		model.Employee{Id: 4, Name: "VP Three", ManagerId: 1},
+		model.Employee{Id: 5, Name: "Lead One", ManagerId: 2},
+		model.Employee{Id: 6, Name: "Lead Two", ManagerId: 3},
+		model.Employee{Id: 7, Name: "Lead Three", ManagerId: 4},
+	}
+
+	// Give the third lead a much larger team than anyone else.
+	for id := 10; id < 22; id++ {
+		chart = append(chart, model.Employee{Id: id, Name: "Engineer", ManagerId: 7})
+	}
+
+	analyser, _ := setupTestAnalyser(chart)
+	outliers := analyser.Stats().SpanOfControl.Outliers
+
+	if len(outliers) != 1 || outliers[0].Manager.Id != 7 || outliers[0].Span != 12 {
+		t.Errorf("The outliers %+v did not contain only Lead Three with 12 reports.", outliers)
+	}
+}
+
+func TestStatsCountsBrokenAndUnreachableEmployees(t *testing.T) {
+	analyser, _ := setupTestAnalyser(model.OrganisationChart{
+		model.Employee{Id: 1, Name: "CEO"},
+		model.Employee{Id: 2, Name: "Orphan", ManagerId: 99},
+		model.Employee{Id: 3, Name: "Loop A", ManagerId: 4},
+		model.Employee{Id: 4, Name: "Loop B", ManagerId: 3},
+	})
+
+	stats := analyser.Stats()
+
+	if stats.Roots != 2 || stats.BrokenChains != 1 || stats.Unreachable != 2 {
+		t.Errorf("The stats %+v did not report 2 roots, 1 broken chain and 2 unreachable employees.", stats)
+	}
+}
+
+func TestAnalysisReturnsStatsAsString(t *testing.T) {
+	analyser, writer := setupTestAnalyser(exampleOrgChart)
+
+	if err := analyser.AnalyseStats(); err != nil {
+		t.Fatalf("There was an error '%s' analysing the given input.", err)
+	}
+
+	expectedOutput := `Headcount: 8
+Roots: 1
+Hierarchy depth: 4
+Widest level: 2 (3 employees)
+Average distance to root: 1.75
+Leaf ratio: 0.50 (4 of 8)
+Span of control: min 1, median 2.0, max 2 across 4 managers
+Span of control outliers: none`
+
+	if writer.contents != expectedOutput {
+		t.Errorf("The received output '%s' was not equal to the expected output '%s'", writer.contents, expectedOutput)
+	}
+}
+
+func TestStatsReportsVacanciesAndTheirOrphanedReports(t *testing.T) {
+	analyser, writer := setupTestAnalyser(model.OrganisationChart{
+		model.Employee{Id: 1, Name: "CEO"},
+		model.Employee{Id: 2, Name: "TBH - Head of Engineering", ManagerId: 1, Vacant: true},
+		model.Employee{Id: 3, Name: "Engineer", ManagerId: 2},
+		model.Employee{Id: 4, Name: "Engineer", ManagerId: 2},
+		model.Employee{Id: 5, Name: "Lead Engineer", ManagerId: 2, Vacant: true},
+		model.Employee{Id: 6, Name: "Head of Sales", ManagerId: 1},
+	})
+
+	stats := analyser.Stats()
+
+	if stats.Headcount != 4 || stats.Positions != 6 || len(stats.Vacancies) != 2 || len(stats.Vacancies[0].OrphanedReports) != 2 || len(stats.Vacancies[1].OrphanedReports) != 0 {
+		t.Errorf("The stats %+v did not report 4 people in 6 positions, with 2 vacancies and 2 orphaned reports.", stats)
+	}
+
+	if err := analyser.AnalyseStats(); err != nil {
+		t.Fatalf("There was an error '%s' analysing the given input.", err)
+	}
+
+	expectedOutput := `Headcount: 4 (6 positions, 2 vacant)
+Roots: 1
+Hierarchy depth: 3
+Widest level: 2 (3 employees)
+Average distance to root: 1.33
+Leaf ratio: 0.67 (4 of 6)
+Span of control: min 2, median 2.5, max 3 across 2 managers
+Span of control outliers: none
+Vacancies: 2, with 2 orphaned reports
+  TBH - Head of Engineering (2) with 2 orphaned reports: Engineer (3), Engineer (4)
+  Lead Engineer (5) with no reports`
+
+	if writer.contents != expectedOutput {
+		t.Errorf("The received output '%s' was not equal to the expected output '%s'", writer.contents, expectedOutput)
+	}
+}
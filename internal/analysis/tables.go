@@ -0,0 +1,67 @@
+package analysis
+
+import (
+	"io"
+	"slices"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+// The lookups the analyser builds from a chart, so they can be saved with it (e.g. in an index file) and handed back to skip building them again.
+// They're only good for the exact chart they were built from.
+type Tables struct {
+	AdjList         map[int][]int                     // solid lines only, as that's what analysers follow by default
+	Relationships   map[[2]int]model.RelationshipType // solid lines only, like AdjList
+	Names           map[string][]int
+	NormalisedNames map[string][]int
+	Reports         map[int][]int
+	Depths          map[int]int   // how far below their root each employee is - only employees with an unbroken chain to a root
+	Ancestors       map[int][]int // each employee's managers 1, 2, 4, 8... levels up, for finding lowest common managers
+}
+
+func BuildTables(chart model.OrganisationChart) Tables {
+	analyser := NewOrganisationChartAnalyser(io.Discard, chart)
+	depths, ancestors := analyser.mapAncestors()
+
+	return Tables{
+		AdjList:         analyser.adjList,
+		Relationships:   analyser.relationshipMap,
+		Names:           analyser.nameMap,
+		NormalisedNames: analyser.normalisedNameMap,
+		Reports:         analyser.reportsMap,
+		Depths:          depths,
+		Ancestors:       ancestors,
+	}
+}
+
+// Uses tables built earlier for this chart instead of building them again. Analysers for charts derived from this one
+// (e.g. a reorg) build their own.
+func WithTables(tables Tables) AnalyserOption {
+	return func(a *organisationChartAnalyser) {
+		a.tables = &tables
+	}
+}
+
+// Paths that follow more than solid lines need their own adjacency list, but everything else in the tables still applies.
+func (a *organisationChartAnalyser) useTables() {
+	a.nameMap = a.tables.Names
+	a.normalisedNameMap = a.tables.NormalisedNames
+	a.reportsMap = a.tables.Reports
+	a.depths = a.tables.Depths
+	a.ancestors = a.tables.Ancestors
+
+	if slices.Equal(a.relationshipTypes, []model.RelationshipType{model.SolidLine}) {
+		a.adjList = a.tables.AdjList
+		a.relationshipMap = a.tables.Relationships
+	} else {
+		a.adjList = a.mapEmployees()
+		a.relationshipMap = a.mapRelationships()
+	}
+}
+
+// An analyser for a chart derived from this one, set up the same way apart from any tables, which were only good for this chart.
+func (a *organisationChartAnalyser) derive(chart model.OrganisationChart) *organisationChartAnalyser {
+	withoutTables := func(d *organisationChartAnalyser) { d.tables = nil }
+
+	return NewOrganisationChartAnalyser(io.Discard, chart, append(slices.Clone(a.options), withoutTables)...)
+}
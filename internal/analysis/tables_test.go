@@ -0,0 +1,88 @@
+package analysis
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+// Dangermouse and Ruth are both roots, Mark and Sarah manage each other, and Orphan reports to someone who isn't in the chart.
+var brokenTablesOrgChart = append(exampleOrgChart[:len(exampleOrgChart):len(exampleOrgChart)],
+	model.Employee{Id: 101, Name: "Ruth"},
+	model.Employee{Id: 102, Name: "Ruth's Report", ManagerId: 101},
+	model.Employee{Id: 103, Name: "Mark", ManagerId: 104},
+	model.Employee{Id: 104, Name: "Sarah", ManagerId: 103},
+	model.Employee{Id: 105, Name: "Orphan", ManagerId: 999},
+)
+
+func TestLowestCommonManagerFromTablesMatchesWalkingChains(t *testing.T) {
+	for desc, chart := range map[string]model.OrganisationChart{"with a valid chart": exampleOrgChart, "with a broken chart": brokenTablesOrgChart} {
+		t.Run(desc, func(t *testing.T) {
+			walking, _ := setupTestAnalyser(chart)
+			indexed, _ := setupTestAnalyser(chart, WithTables(BuildTables(chart)))
+
+			for _, first := range chart {
+				for _, second := range chart {
+					expected, expectedErr := walking.LowestCommonManager(first.Id, second.Id)
+					received, err := indexed.LowestCommonManager(first.Id, second.Id)
+
+					if received.Id != expected.Id || fmt.Sprint(err) != fmt.Sprint(expectedErr) {
+						t.Errorf("The manager %d ('%v') returned for %d and %d was not the expected manager %d ('%v')", received.Id, err, first.Id, second.Id, expected.Id, expectedErr)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestAnalysersWithTablesGiveTheSameAnswers(t *testing.T) {
+	type testCase struct {
+		opts []AnalyserOption
+	}
+
+	testCases := map[string]testCase{
+		"with solid lines":   {},
+		"with dotted lines":  {opts: []AnalyserOption{WithRelationshipTypes(model.SolidLine, model.DottedLine)}},
+		"with every path":    {opts: []AnalyserOption{WithAllShortestPaths()}},
+		"with fuzzy matches": {opts: []AnalyserOption{WithFuzzyAutoSelect()}},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			walking, expected := setupTestAnalyser(exampleOrgChart, tc.opts...)
+			indexed, received := setupTestAnalyser(exampleOrgChart, append(tc.opts, WithTables(BuildTables(exampleOrgChart)))...)
+
+			if err := walking.Analyse("Batman", "Super Ted"); err != nil {
+				t.Fatalf("An error '%s' was returned when none was expected", err)
+			}
+
+			if err := indexed.Analyse("Batman", "Super Ted"); err != nil {
+				t.Fatalf("An error '%s' was returned when none was expected", err)
+			}
+
+			if received.contents != expected.contents {
+				t.Errorf("The received output '%s' was not equal to the expected output '%s'", received.contents, expected.contents)
+			}
+		})
+	}
+}
+
+func TestReorganisingDoesNotReuseTables(t *testing.T) {
+	analyser, _ := setupTestAnalyser(exampleOrgChart, WithTables(BuildTables(exampleOrgChart)))
+	chart, err := analyser.Reorganise([]ReorgOperation{{Kind: MoveEmployee, Subject: "#16", Manager: "#15"}})
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	manager, err := analyser.derive(chart).LowestCommonManager(16, 15)
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	if manager.Id != 15 {
+		t.Errorf("The manager %d returned was not the expected manager %d", manager.Id, 15)
+	}
+}
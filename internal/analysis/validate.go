@@ -0,0 +1,82 @@
+package analysis
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+var (
+	errAnalysisDuplicateId = errors.New("More than one employee has the same ID.")
+	errAnalysisSelfManaged = errors.New("An employee is listed as their own manager.")
+)
+
+// Checks the chart hangs together - unique IDs, managers that exist, and no management cycles.
+// Every problem found is returned, joined together, rather than just the first.
+func (a *organisationChartAnalyser) Validate() error {
+	problems := make([]error, 0)
+	seenIds := make(map[int]model.Employee, len(a.chart))
+
+	for _, employee := range a.chart {
+		if first, seen := seenIds[employee.Id]; seen {
+			problems = append(problems, fmt.Errorf("%w ID %d is used by both %s and %s.", errAnalysisDuplicateId, employee.Id, first.Name, employee.Name))
+		} else {
+			seenIds[employee.Id] = employee
+		}
+
+		for _, manager := range employee.Managers() {
+			if manager.ManagerId == employee.Id {
+				problems = append(problems, fmt.Errorf("%w %s (%d).", errAnalysisSelfManaged, employee.Name, employee.Id))
+				continue
+			}
+
+			if _, ok := a.employeeMap[manager.ManagerId]; !ok {
+				problems = append(problems, fmt.Errorf("%w %s (%d) has a %s line to manager ID %d.", errAnalysisBrokenChain, employee.Name, employee.Id, manager.Type, manager.ManagerId))
+			}
+		}
+	}
+
+	// Each cycle is only reported once, by the first employee in it that we come across.
+	inCycle := make(map[int]bool)
+
+	for _, id := range sortedIds(a.employeeMap) {
+		if inCycle[id] {
+			continue
+		}
+
+		if _, err := a.ChainOfCommand(id); errors.Is(err, errAnalysisManagementCycle) {
+			cycle := a.cycleFrom(id)
+
+			if inCycle[cycle[0]] {
+				continue
+			}
+
+			for _, cycleId := range cycle {
+				inCycle[cycleId] = true
+			}
+
+			// Employees managing themselves have already been reported.
+			if len(cycle) > 1 {
+				problems = append(problems, err)
+			}
+		}
+	}
+
+	return errors.Join(problems...)
+}
+
+// The employees in the cycle that the chain of command from this employee runs into.
+func (a *organisationChartAnalyser) cycleFrom(id int) []int {
+	position := make(map[int]int)
+	chain := make([]int, 0)
+
+	for current := id; ; current = a.employeeMap[current].ManagerId {
+		if start, seen := position[current]; seen {
+			return chain[start:]
+		}
+
+		position[current] = len(chain)
+		chain = append(chain, current)
+	}
+}
@@ -0,0 +1,75 @@
+package analysis
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+func TestValidatingCharts(t *testing.T) {
+	type testCase struct {
+		chart          model.OrganisationChart
+		expectedErrors []error
+	}
+
+	testCases := map[string]testCase{
+		"with a valid chart": {
+			chart: exampleOrgChart,
+		},
+		"with a duplicate ID": {
+			chart: model.OrganisationChart{
+				{Id: 1, Name: "Lawrence"},
+				{Id: 1, Name: "Adrian"},
+			},
+			expectedErrors: []error{errAnalysisDuplicateId},
+		},
+		"with a missing manager": {
+			chart: model.OrganisationChart{
+				{Id: 1, Name: "Lawrence"},
+				{Id: 2, Name: "Adrian", ManagerId: 1, Relationships: []model.Relationship{{ManagerId: 9, Type: model.DottedLine}}},
+			},
+			expectedErrors: []error{errAnalysisBrokenChain},
+		},
+		"with a self managed employee": {
+			chart: model.OrganisationChart{
+				{Id: 1, Name: "Lawrence", ManagerId: 1},
+			},
+			expectedErrors: []error{errAnalysisSelfManaged},
+		},
+		"with a management cycle and a duplicate ID": {
+			chart: model.OrganisationChart{
+				{Id: 1, Name: "Lawrence"},
+				{Id: 2, Name: "Adrian", ManagerId: 3},
+				{Id: 3, Name: "Joshua", ManagerId: 2},
+				{Id: 4, Name: "Natalie", ManagerId: 3},
+				{Id: 4, Name: "Natalie", ManagerId: 1},
+			},
+			expectedErrors: []error{errAnalysisDuplicateId, errAnalysisManagementCycle},
+		},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			analyser, _ := setupTestAnalyser(tc.chart)
+			err := analyser.Validate()
+
+			if len(tc.expectedErrors) == 0 && err != nil {
+				t.Fatalf("An error '%s' was returned when none was expected", err)
+			}
+
+			for _, expected := range tc.expectedErrors {
+				if !errors.Is(err, expected) {
+					t.Errorf("The error '%v' did not include the expected error '%v'", err, expected)
+				}
+			}
+
+			// Each problem should only be reported once.
+			if err != nil {
+				if problems := len(err.(interface{ Unwrap() []error }).Unwrap()); problems != len(tc.expectedErrors) {
+					t.Errorf("%d problems were reported, expected %d: %v", problems, len(tc.expectedErrors), err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"io"
+
+	"github.com/lsg93/org-chart-parser/orgchart"
+)
+
+type chainInput struct {
+	filepath     string
+	employeeName string
+	nameMatching nameMatchingInput
+	reading      chartReadingInput
+}
+
+var (
+	errChainIncorrectArgumentAmount = errors.New("The chain command expects exactly two arguments (filepath, employee name).")
+)
+
+func runChainCommand(args []string, output io.Writer) error {
+	input, err := parseChainArguments(args)
+
+	if err != nil {
+		return err
+	}
+
+	loaded, err := loadChart(input.filepath, input.reading)
+
+	if err != nil {
+		return err
+	}
+
+	if err := writeChartHeading(output, loaded.Metadata); err != nil {
+		return err
+	}
+
+	analyser := loaded.Analyser(append(input.nameMatching.options(), orgchart.WithOutput(output))...)
+	return analyser.AnalyseChainOfCommand(input.employeeName)
+}
+
+func parseChainArguments(args []string) (chainInput, error) {
+	flags := flag.NewFlagSet("chain", flag.ContinueOnError)
+	reading := addChartReadingFlags(flags)
+	nameMatching := addNameMatchingFlags(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return chainInput{}, err
+	}
+
+	args = flags.Args()
+
+	if err := requireArguments(args, 2, errChainIncorrectArgumentAmount); err != nil {
+		return chainInput{}, err
+	}
+
+	return chainInput{filepath: args[0], employeeName: args[1], nameMatching: nameMatching(), reading: reading()}, nil
+}
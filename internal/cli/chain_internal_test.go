@@ -0,0 +1,61 @@
+package cli
+
+import "testing"
+
+func TestParsingChainArguments(t *testing.T) {
+	result, err := parseChainArguments([]string{"path/to/file.txt", "Joshua"})
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	expectedResult := chainInput{filepath: "path/to/file.txt", employeeName: "Joshua"}
+
+	if result != expectedResult {
+		t.Errorf("The struct %v returned was not equal to the expected value %v", result, expectedResult)
+	}
+}
+
+func TestParsingChainArgumentsWithAutoSelect(t *testing.T) {
+	result, err := parseChainArguments([]string{"--auto-select", "path/to/file.txt", "Joshua"})
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	if !result.nameMatching.autoSelect {
+		t.Errorf("The auto-select flag was not set on %v", result)
+	}
+}
+
+func TestParsingInvalidChainArgumentsErrors(t *testing.T) {
+	type testCase struct {
+		input         []string
+		expectedError error
+	}
+
+	testCases := map[string]testCase{
+		"When any/all arguments are empty": {
+			input:         []string{"path/to/file.txt", " "},
+			expectedError: errArgValidationBlankArgumentProvided,
+		},
+		"When there are too few arguments": {
+			input:         []string{"path/to/file.txt"},
+			expectedError: errChainIncorrectArgumentAmount,
+		},
+		"When there are too many arguments": {
+			input:         []string{"path/to/file.txt", "Joshua", "Adrian"},
+			expectedError: errChainIncorrectArgumentAmount,
+		},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			_, err := parseChainArguments(tc.input)
+
+			if err != tc.expectedError {
+				t.Errorf("The error '%v' was returned from validation, but it was not the expected error '%v'", err, tc.expectedError)
+			}
+		})
+	}
+}
@@ -2,70 +2,355 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"slices"
 	"strings"
+	"syscall"
+	"time"
 
-	"github.com/lsg93/org-chart-parser/internal/analysis"
-	"github.com/lsg93/org-chart-parser/internal/parser"
+	"github.com/lsg93/org-chart-parser/orgchart"
 )
 
 type OrgChartParserInput struct {
 	filepath           string
 	firstEmployeeName  string
 	secondEmployeeName string
+	nameMatching       nameMatchingInput
+	shortestOnly       bool
+	allShortest        bool
+	kShortest          int
+	relationships      string // comma separated relationship types, already validated
+	weighted           bool
+	costsFilepath      string
+	avoiding           []string
+	requiring          []string
+	bidirectional      bool
+	reading            chartReadingInput
+	timeout            time.Duration // 0 means no limit
+}
+
+// A flag that can be given more than once, collecting every value.
+type stringListFlag []string
+
+func (s *stringListFlag) String() string {
+	return strings.Join(*s, ", ")
+}
+
+func (s *stringListFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// A date flag, given as 2026-09-01.
+type dateFlag struct {
+	time.Time
+}
+
+func (d *dateFlag) String() string {
+	if d.IsZero() {
+		return ""
+	}
+
+	return d.Format(time.DateOnly)
+}
+
+func (d *dateFlag) Set(value string) error {
+	date, err := time.Parse(time.DateOnly, value)
+
+	if err != nil {
+		return errArgValidationInvalidDate
+	}
+
+	d.Time = date
+	return nil
+}
+
+// Flags shared by every command that reads a chart.
+type chartReadingInput struct {
+	asOf           time.Time // zero means today
+	guessVacancies bool
+}
+
+// Registers --as-of and --guess-vacancies, shared by every command that reads a single chart.
+func addChartReadingFlags(flags *flag.FlagSet) func() chartReadingInput {
+	var asOf dateFlag
+	flags.Var(&asOf, "as-of", "Use the chart as it was on this date (e.g. 2025-03-01), from effective-dated rows or a directory of dated snapshots. Defaults to today.")
+	guessVacancies := addGuessVacanciesFlag(flags)
+
+	return func() chartReadingInput {
+		return chartReadingInput{asOf: asOf.Time, guessVacancies: *guessVacancies}
+	}
+}
+
+// Commands that always read charts as they are today only get --guess-vacancies.
+func addGuessVacanciesFlag(flags *flag.FlagSet) *bool {
+	return flags.Bool("guess-vacancies", false, "Treat positions named like an open role (TBH, TBD, Vacant or Vacancy) as vacant, not just those marked yes in the Vacant column.")
+}
+
+func (input chartReadingInput) options() []orgchart.ParseOption {
+	opts := make([]orgchart.ParseOption, 0)
+
+	if !input.asOf.IsZero() {
+		opts = append(opts, orgchart.AsOf(input.asOf))
+	}
+
+	if input.guessVacancies {
+		opts = append(opts, orgchart.GuessVacancies())
+	}
+
+	return opts
+}
+
+// Flags shared by every command that looks employees up by name.
+type nameMatchingInput struct {
+	autoSelect bool
+	strict     bool
 }
 
 var (
 	errArgValidationBlankArgumentProvided   = errors.New("One, or many of the arguments provided are blank.")
 	errArgValidationIncorrectArgumentAmount = errors.New("One or more of the expected arguments (filepath, start name, target name) have not been provided.")
 	errCouldNotReadFile                     = errors.New("There was an error reading the file.")
+	errArgValidationInvalidPathCount        = errors.New("The number of paths to show (--k) must be a positive number.")
+	errArgValidationConflictingSearchModes  = errors.New("Only one of --all and --k can be used at a time.")
+	errArgValidationViaWithSearchMode       = errors.New("--via can't be used with --all or --k, as only the shortest path through everyone given is found.")
+	errArgValidationBidirectionalWeighted   = errors.New("A bidirectional search can't be weighted - use one of --bidirectional and --weighted.")
+	errArgValidationUnknownRelationship     = errors.New("One of the relationship types provided is not recognised - use solid, dotted or interim.")
+	errArgValidationInvalidDate             = errors.New("Dates should be given as year-month-day, e.g. 2026-09-01.")
+	errArgValidationInvalidTimeout          = errors.New("The timeout (--timeout) can't be negative - leave it out to search for as long as it takes.")
+	errPathTimedOut                         = errors.New("The search didn't finish within the time given by --timeout.")
 )
 
+// Subcommands are looked up by the first argument.
+// Anything that isn't a known command falls through to the original path query, so existing usage keeps working.
+var commands = map[string]func(args []string, output io.Writer) error{
+	"add":      addCommand.run,
+	"chain":    runChainCommand,
+	"diff":     runDiffCommand,
+	"generate": runGenerateCommand,
+	"index":    runIndexCommand,
+	"move":     moveCommand.run,
+	"remove":   removeCommand.run,
+	"rename":   renameCommand.run,
+	"reorg":    runReorgCommand,
+	"reports":  runReportsCommand,
+	"serve":    runServeCommand,
+	"stats":    runStatsCommand,
+}
+
 func Run() {
-	input, err := parseArguments()
+	var err error
+
+	if command, ok := selectCommand(os.Args[1:]); ok {
+		err = command(os.Args[2:], os.Stdout)
+	} else {
+		err = runPathCommand(os.Stdout)
+	}
 
 	if err != nil {
 		fmt.Fprintln(os.Stderr, "Error:", err)
 		os.Exit(1)
 	}
+}
+
+func selectCommand(args []string) (func(args []string, output io.Writer) error, bool) {
+	if len(args) == 0 {
+		return nil, false
+	}
 
-	data, err := readFile(input.filepath)
+	command, ok := commands[args[0]]
+	return command, ok
+}
+
+func runPathCommand(output io.Writer) error {
+	input, err := parseArguments()
 
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
-		os.Exit(1)
+		return err
 	}
 
-	parser, err := parser.NewOrganisationChartParser(bytes.NewReader(data))
+	// Ctrl+C stops a long search (or parse) cleanly, and --timeout stops it without anyone having to be there.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
-		os.Exit(1)
+	if input.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, input.timeout)
+		defer cancel()
 	}
 
-	chart, err := parser.Parse()
+	err = findPath(ctx, input, output)
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return errPathTimedOut
+	}
+
+	return err
+}
+
+func findPath(ctx context.Context, input OrgChartParserInput, output io.Writer) error {
+	loaded, err := loadChartContext(ctx, input.filepath, input.reading)
 
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
-		os.Exit(1)
+		return err
+	}
+
+	if err := writeChartHeading(output, loaded.Metadata); err != nil {
+		return err
 	}
 
-	analyser := analysis.NewOrganisationChartAnalyser(os.Stdout, chart)
-	err = analyser.Analyse(input.firstEmployeeName, input.secondEmployeeName)
+	opts := append(input.options(), orgchart.WithOutput(output))
+
+	if input.weighted {
+		rules, err := loadCostRules(input.costsFilepath)
+
+		if err != nil {
+			return err
+		}
+
+		opts = append(opts, orgchart.WithWeightedSearch(rules))
+	}
+
+	analyser := loaded.Analyser(opts...)
+	return analyser.AnalyseContext(ctx, input.firstEmployeeName, input.secondEmployeeName)
+}
+
+// Without a rules file every hop costs the same.
+func loadCostRules(path string) (orgchart.CostRules, error) {
+	if path == "" {
+		return orgchart.DefaultCostRules(), nil
+	}
+
+	data, err := readFile(path)
 
 	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
-		os.Exit(1)
+		return orgchart.CostRules{}, err
 	}
+
+	return orgchart.ParseCostRules(bytes.NewReader(data))
+}
+
+func (input OrgChartParserInput) options() []orgchart.Option {
+	opts := input.nameMatching.options()
+
+	if input.shortestOnly {
+		opts = append(opts, orgchart.WithShortestPathOnly())
+	}
+
+	if input.allShortest {
+		opts = append(opts, orgchart.WithAllShortestPaths())
+	}
+
+	if input.kShortest > 0 {
+		opts = append(opts, orgchart.WithKShortestPaths(input.kShortest))
+	}
+
+	if input.bidirectional {
+		opts = append(opts, orgchart.WithBidirectionalSearch())
+	}
+
+	if len(input.avoiding) > 0 {
+		opts = append(opts, orgchart.WithAvoiding(input.avoiding...))
+	}
+
+	if len(input.requiring) > 0 {
+		opts = append(opts, orgchart.WithRequiring(input.requiring...))
+	}
+
+	if input.relationships != "" {
+		// The error can be ignored, as the types were validated when the arguments were parsed.
+		types, _ := parseRelationshipTypes(input.relationships)
+		opts = append(opts, orgchart.WithRelationshipTypes(types...))
+	}
+
+	return opts
+}
+
+// Registers the name matching flags, returning a function to read them back once the flags have been parsed.
+func addNameMatchingFlags(flags *flag.FlagSet) func() nameMatchingInput {
+	autoSelect := flags.Bool("auto-select", false, "Use the closest matching name when a name can't be found, as long as only one name is closest.")
+	strict := flags.Bool("strict", false, "Error with a list of candidates when a name matches more than one employee, instead of trying them all.")
+
+	return func() nameMatchingInput {
+		return nameMatchingInput{autoSelect: *autoSelect, strict: *strict}
+	}
+}
+
+// Names are always matched case and accent insensitively - these opt in to picking the closest fuzzy match, or refusing to guess.
+func (input nameMatchingInput) options() []orgchart.Option {
+	opts := make([]orgchart.Option, 0)
+
+	if input.autoSelect {
+		opts = append(opts, orgchart.WithFuzzyAutoSelect())
+	}
+
+	if input.strict {
+		opts = append(opts, orgchart.WithStrictNames())
+	}
+
+	return opts
 }
 
 func parseArguments() (OrgChartParserInput, error) {
+	nameMatching := addNameMatchingFlags(flag.CommandLine)
+	fromId := flag.Int("from-id", 0, "Start the path at the employee with this ID instead of giving a start name.")
+	toId := flag.Int("to-id", 0, "End the path at the employee with this ID instead of giving a target name.")
+	shortestOnly := flag.Bool("shortest", false, "Only show the shortest path when duplicate names give several candidates.")
+	allShortest := flag.Bool("all", false, "Show every path that shares the shortest length.")
+	kShortest := flag.Int("k", 0, "Show up to this many of the shortest simple paths.")
+	relationships := flag.String("relationships", "", "Comma separated relationship types a path may use: solid, dotted, interim. Defaults to solid.")
+	weighted := flag.Bool("weighted", false, "Rank paths by the total cost of their hops instead of the number of hops.")
+	costsFilepath := flag.String("costs", "", "A rules file of hop costs for a weighted search - implies --weighted.")
+	bidirectional := flag.Bool("bidirectional", false, "Search from both ends at once - much faster on very large charts.")
+	var avoiding, requiring stringListFlag
+	flag.Var(&avoiding, "avoid", "Don't route through anyone matching this ID, name or attribute selector. Can be repeated.")
+	flag.Var(&requiring, "via", "Route through someone matching this ID, name or attribute selector. Can be repeated, and is followed in order.")
+	reading := addChartReadingFlags(flag.CommandLine)
+	timeout := flag.Duration("timeout", 0, "Give up if the path hasn't been found after this long (e.g. 30s). Defaults to no limit.")
 	flag.Parse()
 	args := flag.Args()
 
+	if *kShortest < 0 {
+		return OrgChartParserInput{}, errArgValidationInvalidPathCount
+	}
+
+	if *timeout < 0 {
+		return OrgChartParserInput{}, errArgValidationInvalidTimeout
+	}
+
+	if *allShortest && *kShortest > 0 {
+		return OrgChartParserInput{}, errArgValidationConflictingSearchModes
+	}
+
+	if len(requiring) > 0 && (*allShortest || *kShortest > 0) {
+		return OrgChartParserInput{}, errArgValidationViaWithSearchMode
+	}
+
+	if *bidirectional && (*weighted || *costsFilepath != "") {
+		return OrgChartParserInput{}, errArgValidationBidirectionalWeighted
+	}
+
+	if *relationships != "" {
+		if _, err := parseRelationshipTypes(*relationships); err != nil {
+			return OrgChartParserInput{}, err
+		}
+	}
+
+	// Endpoints given as flags are slotted in as ID selectors, so the positional arguments are validated the same way either way.
+	if *fromId != 0 {
+		args = slices.Insert(args, min(1, len(args)), fmt.Sprintf("#%d", *fromId))
+	}
+
+	if *toId != 0 {
+		args = append(args, fmt.Sprintf("#%d", *toId))
+	}
+
 	err := validateArguments(args)
 
 	if err != nil {
@@ -76,14 +361,31 @@ func parseArguments() (OrgChartParserInput, error) {
 		filepath:           args[0],
 		firstEmployeeName:  args[1],
 		secondEmployeeName: args[2],
+		nameMatching:       nameMatching(),
+		shortestOnly:       *shortestOnly,
+		allShortest:        *allShortest,
+		kShortest:          *kShortest,
+		relationships:      *relationships,
+		weighted:           *weighted || *costsFilepath != "",
+		costsFilepath:      *costsFilepath,
+		avoiding:           avoiding,
+		requiring:          requiring,
+		bidirectional:      *bidirectional,
+		reading:            reading(),
+		timeout:            *timeout,
 	}
 
 	return res, nil
 }
 
 func validateArguments(args []string) error {
-	if len(args) != 3 {
-		return errArgValidationIncorrectArgumentAmount
+	return requireArguments(args, 3, errArgValidationIncorrectArgumentAmount)
+}
+
+// Commands all take a fixed number of positional arguments, none of which may be blank.
+func requireArguments(args []string, amount int, amountErr error) error {
+	if len(args) != amount {
+		return amountErr
 	}
 
 	for _, item := range args {
@@ -95,6 +397,56 @@ func validateArguments(args []string) error {
 	return nil
 }
 
+func parseRelationshipTypes(s string) ([]orgchart.RelationshipType, error) {
+	types := make([]orgchart.RelationshipType, 0)
+
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		index := slices.IndexFunc(orgchart.RelationshipTypes, func(t orgchart.RelationshipType) bool {
+			return t.String() == name
+		})
+
+		if index < 0 {
+			return nil, errArgValidationUnknownRelationship
+		}
+
+		types = append(types, orgchart.RelationshipTypes[index])
+	}
+
+	return types, nil
+}
+
+// Shared by every command - reads the chart at the given path as it was on the date, along with any front matter.
+// The path can be a single file, or a directory of dated snapshots. A zero date means today.
+func loadChart(path string, reading chartReadingInput) (orgchart.LoadedChart, error) {
+	return loadChartContext(context.Background(), path, reading)
+}
+
+// The same as loadChart, but stops once the context is cancelled - for commands that run for a while, or until they're stopped.
+func loadChartContext(ctx context.Context, path string, reading chartReadingInput) (orgchart.LoadedChart, error) {
+	return orgchart.LoadContext(ctx, path, reading.options()...)
+}
+
+// Charts with a name or date in their front matter get a heading above the output, e.g. "Chart: Avengers Initiative, as of 2026-09-01".
+func writeChartHeading(output io.Writer, metadata orgchart.Metadata) error {
+	var heading string
+
+	switch {
+	case metadata.Name != "" && !metadata.AsOf.IsZero():
+		heading = fmt.Sprintf("Chart: %s, as of %s", metadata.Name, metadata.AsOf.Format(time.DateOnly))
+	case metadata.Name != "":
+		heading = fmt.Sprintf("Chart: %s", metadata.Name)
+	case !metadata.AsOf.IsZero():
+		heading = fmt.Sprintf("Chart as of %s", metadata.AsOf.Format(time.DateOnly))
+	default:
+		return nil
+	}
+
+	_, err := io.WriteString(output, heading+"\n")
+
+	return err
+}
+
 func readFile(path string) ([]byte, error) {
 	_, err := os.Stat(path)
 	if err != nil {
@@ -1,9 +1,19 @@
 package cli
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"flag"
 	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
 )
 
 func TestParsingValidCliArgumentsReturnsInputType(t *testing.T) {
@@ -36,7 +46,7 @@ func TestParsingValidCliArgumentsReturnsInputType(t *testing.T) {
 		t.Fatalf("An error '%s' was returned when none was expected", err)
 	}
 
-	if result != expectedResult {
+	if !reflect.DeepEqual(result, expectedResult) {
 		t.Errorf("The struct %v returned was not equal to the expected value %v", result, expectedResult)
 	}
 }
@@ -77,3 +87,250 @@ func TestParsingInvalidCliArgumentsErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestSelectingCommandsFallsBackToPathQuery(t *testing.T) {
+	if _, ok := selectCommand([]string{"chain", "path/to/file.txt", "Joshua"}); !ok {
+		t.Errorf("The chain command was not selected.")
+	}
+
+	if _, ok := selectCommand([]string{"path/to/file.txt", "Joshua", "Lawrence"}); ok {
+		t.Errorf("A command was selected for a plain path query.")
+	}
+}
+
+func mockCommandLine(t *testing.T, mockArgs []string) {
+	flag.CommandLine = flag.NewFlagSet(mockArgs[0], flag.ExitOnError)
+	t.Cleanup(func() {
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+	})
+
+	originalArgs := os.Args
+	os.Args = mockArgs
+	t.Cleanup(func() {
+		os.Args = originalArgs
+	})
+}
+
+func TestParsingIdFlagsReturnsSelectors(t *testing.T) {
+	type testCase struct {
+		input          []string
+		expectedResult OrgChartParserInput
+	}
+
+	testCases := map[string]testCase{
+		"with a start ID": {
+			input:          []string{"test", "--from-id", "17", "path/to/file.txt", "Lawrence"},
+			expectedResult: OrgChartParserInput{filepath: "path/to/file.txt", firstEmployeeName: "#17", secondEmployeeName: "Lawrence"},
+		},
+		"with a target ID": {
+			input:          []string{"test", "--to-id", "3", "path/to/file.txt", "Joshua"},
+			expectedResult: OrgChartParserInput{filepath: "path/to/file.txt", firstEmployeeName: "Joshua", secondEmployeeName: "#3"},
+		},
+		"with both IDs, strict matching and shortest only": {
+			input: []string{"test", "--from-id", "17", "--to-id", "3", "--strict", "--shortest", "path/to/file.txt"},
+			expectedResult: OrgChartParserInput{
+				filepath:           "path/to/file.txt",
+				firstEmployeeName:  "#17",
+				secondEmployeeName: "#3",
+				nameMatching:       nameMatchingInput{strict: true},
+				shortestOnly:       true,
+			},
+		},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			mockCommandLine(t, tc.input)
+			result, err := parseArguments()
+
+			if err != nil {
+				t.Fatalf("An error '%s' was returned when none was expected", err)
+			}
+
+			if !reflect.DeepEqual(result, tc.expectedResult) {
+				t.Errorf("The struct %v returned was not equal to the expected value %v", result, tc.expectedResult)
+			}
+		})
+	}
+}
+
+func TestParsingPathSearchModes(t *testing.T) {
+	mockCommandLine(t, []string{"test", "--k", "3", "path/to/file.txt", "Joshua", "Lawrence"})
+	result, err := parseArguments()
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	if result.kShortest != 3 || result.allShortest {
+		t.Errorf("The struct %v returned did not ask for the 3 shortest paths.", result)
+	}
+}
+
+func TestParsingConflictingPathSearchModesErrors(t *testing.T) {
+	type testCase struct {
+		input         []string
+		expectedError error
+	}
+
+	testCases := map[string]testCase{
+		"with --all and --k": {
+			input:         []string{"test", "--all", "--k", "3", "path/to/file.txt", "Joshua", "Lawrence"},
+			expectedError: errArgValidationConflictingSearchModes,
+		},
+		"with --via and --all": {
+			input:         []string{"test", "--via", "Adrian", "--all", "path/to/file.txt", "Joshua", "Lawrence"},
+			expectedError: errArgValidationViaWithSearchMode,
+		},
+		"with --via and --k": {
+			input:         []string{"test", "--via", "Adrian", "--k", "2", "path/to/file.txt", "Joshua", "Lawrence"},
+			expectedError: errArgValidationViaWithSearchMode,
+		},
+		"with --bidirectional and --weighted": {
+			input:         []string{"test", "--bidirectional", "--weighted", "path/to/file.txt", "Joshua", "Lawrence"},
+			expectedError: errArgValidationBidirectionalWeighted,
+		},
+		"with a negative --k": {
+			input:         []string{"test", "--k", "-1", "path/to/file.txt", "Joshua", "Lawrence"},
+			expectedError: errArgValidationInvalidPathCount,
+		},
+		"with a negative --timeout": {
+			input:         []string{"test", "--timeout", "-1s", "path/to/file.txt", "Joshua", "Lawrence"},
+			expectedError: errArgValidationInvalidTimeout,
+		},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			mockCommandLine(t, tc.input)
+			_, err := parseArguments()
+
+			if err != tc.expectedError {
+				t.Errorf("The error '%v' was returned, but it was not the expected error '%v'", err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestPathSearchesStopAtTheirDeadline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "avengers.txt")
+
+	if err := os.WriteFile(path, []byte("| ID | Name | Manager ID |\n| 1 | Nick Fury | |\n| 2 | Hawkeye | 1 |"), 0o644); err != nil {
+		t.Fatalf("An error '%s' occurred writing the test chart", err)
+	}
+
+	mockCommandLine(t, []string{"test", "--timeout", "30s", path, "Hawkeye", "Nick Fury"})
+	input, err := parseArguments()
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	if input.timeout != 30*time.Second {
+		t.Errorf("The timeout %s was not the expected timeout 30s.", input.timeout)
+	}
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now())
+	defer cancel()
+
+	var output bytes.Buffer
+
+	if err := findPath(ctx, input, &output); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("The error '%v' was returned, but it was not the expected error '%v'", err, context.DeadlineExceeded)
+	}
+
+	if output.Len() > 0 {
+		t.Errorf("A search past its deadline wrote '%s', expected nothing.", output.String())
+	}
+}
+
+func TestParsingRelationshipTypes(t *testing.T) {
+	types, err := parseRelationshipTypes("solid, Dotted,interim")
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	expected := []model.RelationshipType{model.SolidLine, model.DottedLine, model.Interim}
+
+	if !slices.Equal(types, expected) {
+		t.Errorf("The types %v were not the expected types %v", types, expected)
+	}
+
+	if _, err := parseRelationshipTypes("solid,matrix"); err != errArgValidationUnknownRelationship {
+		t.Errorf("The error '%v' was returned, but it was not the expected error '%v'", err, errArgValidationUnknownRelationship)
+	}
+}
+
+func TestParsingCostsImpliesWeightedSearch(t *testing.T) {
+	mockCommandLine(t, []string{"test", "--costs", "costs.txt", "path/to/file.txt", "Joshua", "Lawrence"})
+	result, err := parseArguments()
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	if !result.weighted || result.costsFilepath != "costs.txt" {
+		t.Errorf("The struct %v returned did not ask for a weighted search using costs.txt.", result)
+	}
+}
+
+func TestParsingRepeatedConstraintFlags(t *testing.T) {
+	mockCommandLine(t, []string{"test", "--avoid", "#17", "--avoid", "department=Ops", "--via", "Nick Fury", "path/to/file.txt", "Joshua", "Lawrence"})
+	result, err := parseArguments()
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	if !slices.Equal(result.avoiding, []string{"#17", "department=Ops"}) || !slices.Equal(result.requiring, []string{"Nick Fury"}) {
+		t.Errorf("The struct %v returned did not hold the constraints in order.", result)
+	}
+}
+
+func TestWritingChartHeadings(t *testing.T) {
+	asOf := time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC)
+
+	type testCase struct {
+		metadata       model.ChartMetadata
+		expectedOutput string
+	}
+
+	testCases := map[string]testCase{
+		"with a name and date":     {metadata: model.ChartMetadata{Name: "Avengers Initiative", AsOf: asOf, Source: "HR export"}, expectedOutput: "Chart: Avengers Initiative, as of 2026-09-01\n"},
+		"with only a name":         {metadata: model.ChartMetadata{Name: "Avengers Initiative"}, expectedOutput: "Chart: Avengers Initiative\n"},
+		"with only a date":         {metadata: model.ChartMetadata{AsOf: asOf}, expectedOutput: "Chart as of 2026-09-01\n"},
+		"without any front matter": {metadata: model.ChartMetadata{}, expectedOutput: ""},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			var output bytes.Buffer
+
+			if err := writeChartHeading(&output, tc.metadata); err != nil {
+				t.Fatalf("An error '%s' was returned when none was expected", err)
+			}
+
+			if output.String() != tc.expectedOutput {
+				t.Errorf("The received output '%s' was not equal to the expected output '%s'", output.String(), tc.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestParsingAsOfDates(t *testing.T) {
+	result, err := parseStatsArguments([]string{"--as-of", "2025-03-01", "path/to/file.txt"})
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	if !result.reading.asOf.Equal(time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("The date %s was not the expected date.", result.reading.asOf)
+	}
+
+	// The flag package wraps the error in its own message, without keeping the original.
+	if _, err := parseStatsArguments([]string{"--as-of", "last year", "path/to/file.txt"}); err == nil || !strings.Contains(err.Error(), errArgValidationInvalidDate.Error()) {
+		t.Errorf("The error '%v' was returned from validation, but it was not the expected error '%v'", err, errArgValidationInvalidDate)
+	}
+}
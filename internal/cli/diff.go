@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"slices"
+
+	"github.com/lsg93/org-chart-parser/orgchart"
+)
+
+type diffInput struct {
+	previousFilepath string
+	filepath         string
+	format           string // blank uses the format from the chart's front matter, or text
+	reading          chartReadingInput
+}
+
+var (
+	errDiffIncorrectArgumentAmount = errors.New("The diff command expects exactly two arguments (previous filepath, filepath).")
+	errDiffUnknownFormat           = errors.New("The diff command only supports the text and json formats.")
+)
+
+var diffFormats = []string{orgchart.ReportFormatText, orgchart.ReportFormatJSON}
+
+func runDiffCommand(args []string, output io.Writer) error {
+	input, err := parseDiffArguments(args)
+
+	if err != nil {
+		return err
+	}
+
+	previous, err := loadChart(input.previousFilepath, input.reading)
+
+	if err != nil {
+		return err
+	}
+
+	loaded, err := loadChart(input.filepath, input.reading)
+
+	if err != nil {
+		return err
+	}
+
+	format := input.format
+
+	if format == "" && slices.Contains(diffFormats, loaded.Metadata.Format) {
+		format = loaded.Metadata.Format
+	}
+
+	if format == "" {
+		format = orgchart.ReportFormatText
+	}
+
+	// A heading would stop the JSON from being valid.
+	if format == orgchart.ReportFormatText {
+		if err := writeChartHeading(output, loaded.Metadata); err != nil {
+			return err
+		}
+	}
+
+	analyser := loaded.Analyser(orgchart.WithOutput(output))
+	return analyser.AnalyseDiff(previous.Chart, format)
+}
+
+func parseDiffArguments(args []string) (diffInput, error) {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	format := flags.String("format", "", "The output format (text or json) - defaults to the format in the chart's front matter, or text.")
+	guessVacancies := addGuessVacanciesFlag(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return diffInput{}, err
+	}
+
+	args = flags.Args()
+
+	if err := requireArguments(args, 2, errDiffIncorrectArgumentAmount); err != nil {
+		return diffInput{}, err
+	}
+
+	if *format != "" && !slices.Contains(diffFormats, *format) {
+		return diffInput{}, errDiffUnknownFormat
+	}
+
+	return diffInput{previousFilepath: args[0], filepath: args[1], format: *format, reading: chartReadingInput{guessVacancies: *guessVacancies}}, nil
+}
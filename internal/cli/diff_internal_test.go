@@ -0,0 +1,61 @@
+package cli
+
+import "testing"
+
+func TestParsingDiffArguments(t *testing.T) {
+	type testCase struct {
+		input          []string
+		expectedResult diffInput
+	}
+
+	testCases := map[string]testCase{
+		"without a format": {
+			input:          []string{"august.txt", "september.txt"},
+			expectedResult: diffInput{previousFilepath: "august.txt", filepath: "september.txt"},
+		},
+		"with a format": {
+			input:          []string{"--format", "json", "august.txt", "september.txt"},
+			expectedResult: diffInput{previousFilepath: "august.txt", filepath: "september.txt", format: "json"},
+		},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			result, err := parseDiffArguments(tc.input)
+
+			if err != nil {
+				t.Fatalf("An error '%s' was returned when none was expected", err)
+			}
+
+			if result != tc.expectedResult {
+				t.Errorf("The struct %v returned was not equal to the expected value %v", result, tc.expectedResult)
+			}
+		})
+	}
+}
+
+func TestParsingInvalidDiffArgumentsErrors(t *testing.T) {
+	type testCase struct {
+		input         []string
+		expectedError error
+	}
+
+	testCases := map[string]testCase{
+		"with one file": {
+			input:         []string{"august.txt"},
+			expectedError: errDiffIncorrectArgumentAmount,
+		},
+		"with an unknown format": {
+			input:         []string{"--format", "table", "august.txt", "september.txt"},
+			expectedError: errDiffUnknownFormat,
+		},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			if _, err := parseDiffArguments(tc.input); err != tc.expectedError {
+				t.Errorf("The error '%v' was returned from validation, but it was not the expected error '%v'", err, tc.expectedError)
+			}
+		})
+	}
+}
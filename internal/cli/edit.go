@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/lsg93/org-chart-parser/orgchart"
+)
+
+type editInput struct {
+	filepath     string
+	operation    orgchart.ReorgOperation
+	nameMatching nameMatchingInput
+	dryRun       bool
+}
+
+var (
+	errAddIncorrectArgumentAmount    = errors.New("The add command expects exactly three arguments (filepath, new employee name, manager name).")
+	errRemoveIncorrectArgumentAmount = errors.New("The remove command expects exactly two arguments (filepath, employee name).")
+	errMoveIncorrectArgumentAmount   = errors.New("The move command expects exactly three arguments (filepath, employee name, new manager name).")
+	errRenameIncorrectArgumentAmount = errors.New("The rename command expects exactly three arguments (filepath, employee name, new name).")
+	errCouldNotWriteFile             = errors.New("There was an error saving the file.")
+)
+
+// The edit commands are the same as a one line reorg script, saved back into the chart file.
+// Each one knows how many arguments it takes after the filepath, and how to turn them into an operation.
+type editCommand struct {
+	name      string
+	arguments int
+	amountErr error
+	operation func(args []string) orgchart.ReorgOperation
+}
+
+var (
+	addCommand = editCommand{"add", 2, errAddIncorrectArgumentAmount, func(args []string) orgchart.ReorgOperation {
+		return orgchart.ReorgOperation{Kind: orgchart.AddEmployee, Subject: args[0], Manager: args[1]}
+	}}
+	removeCommand = editCommand{"remove", 1, errRemoveIncorrectArgumentAmount, func(args []string) orgchart.ReorgOperation {
+		return orgchart.ReorgOperation{Kind: orgchart.RemoveEmployee, Subject: args[0]}
+	}}
+	moveCommand = editCommand{"move", 2, errMoveIncorrectArgumentAmount, func(args []string) orgchart.ReorgOperation {
+		return orgchart.ReorgOperation{Kind: orgchart.MoveEmployee, Subject: args[0], Manager: args[1]}
+	}}
+	renameCommand = editCommand{"rename", 2, errRenameIncorrectArgumentAmount, func(args []string) orgchart.ReorgOperation {
+		return orgchart.ReorgOperation{Kind: orgchart.RenameEmployee, Subject: args[0], Name: args[1]}
+	}}
+)
+
+// Makes the change, checks the chart is still valid, and saves it - only the rows that changed are touched.
+// With --dry-run the changes are shown but the file is left alone.
+func (command editCommand) run(args []string, output io.Writer) error {
+	input, err := command.parseArguments(args)
+
+	if err != nil {
+		return err
+	}
+
+	data, err := readFile(input.filepath)
+
+	if err != nil {
+		return err
+	}
+
+	document, err := orgchart.ReadDocument(bytes.NewReader(data))
+
+	if err != nil {
+		return err
+	}
+
+	original := document.Chart()
+	analyser := orgchart.NewAnalyser(original, input.nameMatching.options()...)
+	chart, err := analyser.Reorganise([]orgchart.ReorgOperation{input.operation})
+
+	if err != nil {
+		return err
+	}
+
+	if err := document.Apply(chart); err != nil {
+		return err
+	}
+
+	if err := orgchart.NewAnalyser(chart, orgchart.WithOutput(output)).AnalyseDiff(original, orgchart.ReportFormatText); err != nil {
+		return err
+	}
+
+	if input.dryRun {
+		_, err = fmt.Fprintf(output, "\n\nDry run - %s has not been changed.\n", input.filepath)
+		return err
+	}
+
+	if err := saveFile(input.filepath, []byte(document.String())); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(output, "\n\nSaved %s.\n", input.filepath)
+
+	return err
+}
+
+func (command editCommand) parseArguments(args []string) (editInput, error) {
+	flags := flag.NewFlagSet(command.name, flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "Show the changes without saving them.")
+	nameMatching := addNameMatchingFlags(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return editInput{}, err
+	}
+
+	args = flags.Args()
+
+	if err := requireArguments(args, command.arguments+1, command.amountErr); err != nil {
+		return editInput{}, err
+	}
+
+	return editInput{filepath: args[0], operation: command.operation(args[1:]), nameMatching: nameMatching(), dryRun: *dryRun}, nil
+}
+
+// Writes to a temporary file next to the original and renames it over the top, so a failed save never leaves half a chart behind.
+// A new file gets the usual permissions, and an existing one keeps its own.
+func saveFile(path string, data []byte) error {
+	mode := os.FileMode(0o644)
+
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode()
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+
+	if err != nil {
+		return errCouldNotWriteFile
+	}
+
+	defer os.Remove(file.Name())
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return errCouldNotWriteFile
+	}
+
+	if err := file.Chmod(mode); err != nil {
+		file.Close()
+		return errCouldNotWriteFile
+	}
+
+	if err := file.Close(); err != nil {
+		return errCouldNotWriteFile
+	}
+
+	if err := os.Rename(file.Name(), path); err != nil {
+		return errCouldNotWriteFile
+	}
+
+	return nil
+}
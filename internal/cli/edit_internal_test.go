@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lsg93/org-chart-parser/internal/analysis"
+)
+
+const editTestChart = `# Avengers
+| ID | Name        | Manager ID |
+| 1  | Nick Fury   |            |
+| 2  | Iron Man    | 1          |
+| 6  | Black Widow | 2          |
+`
+
+func TestParsingEditArguments(t *testing.T) {
+	type testCase struct {
+		command        editCommand
+		input          []string
+		expectedResult editInput
+	}
+
+	testCases := map[string]testCase{
+		"for add": {
+			command:        addCommand,
+			input:          []string{"chart.txt", "Maria Hill", "Nick Fury"},
+			expectedResult: editInput{filepath: "chart.txt", operation: analysis.ReorgOperation{Kind: analysis.AddEmployee, Subject: "Maria Hill", Manager: "Nick Fury"}},
+		},
+		"for remove, as a dry run": {
+			command:        removeCommand,
+			input:          []string{"--dry-run", "chart.txt", "Hawkeye"},
+			expectedResult: editInput{filepath: "chart.txt", operation: analysis.ReorgOperation{Kind: analysis.RemoveEmployee, Subject: "Hawkeye"}, dryRun: true},
+		},
+		"for move, with name matching flags": {
+			command:        moveCommand,
+			input:          []string{"--auto-select", "chart.txt", "Hawkeye", "#1"},
+			expectedResult: editInput{filepath: "chart.txt", operation: analysis.ReorgOperation{Kind: analysis.MoveEmployee, Subject: "Hawkeye", Manager: "#1"}, nameMatching: nameMatchingInput{autoSelect: true}},
+		},
+		"for rename": {
+			command:        renameCommand,
+			input:          []string{"chart.txt", "Black Widow", "Natasha Romanoff"},
+			expectedResult: editInput{filepath: "chart.txt", operation: analysis.ReorgOperation{Kind: analysis.RenameEmployee, Subject: "Black Widow", Name: "Natasha Romanoff"}},
+		},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			result, err := tc.command.parseArguments(tc.input)
+
+			if err != nil {
+				t.Fatalf("An error '%s' was returned when none was expected", err)
+			}
+
+			if result != tc.expectedResult {
+				t.Errorf("The struct %v returned was not equal to the expected value %v", result, tc.expectedResult)
+			}
+		})
+	}
+
+	if _, err := moveCommand.parseArguments([]string{"chart.txt", "Hawkeye"}); err != errMoveIncorrectArgumentAmount {
+		t.Errorf("The error '%v' was returned from validation, but it was not the expected error '%v'", err, errMoveIncorrectArgumentAmount)
+	}
+}
+
+func TestEditingChartFiles(t *testing.T) {
+	type testCase struct {
+		command      editCommand
+		input        []string
+		expectError  bool
+		expectedFile string
+	}
+
+	testCases := map[string]testCase{
+		"saving a change": {
+			command: renameCommand,
+			input:   []string{"Black Widow", "Natasha Romanoff"},
+			expectedFile: `# Avengers
+| ID | Name             | Manager ID |
+| 1  | Nick Fury        |            |
+| 2  | Iron Man         | 1          |
+| 6  | Natasha Romanoff | 2          |
+`,
+		},
+		"as a dry run": {
+			command:      moveCommand,
+			input:        []string{"--dry-run", "Black Widow", "Nick Fury"},
+			expectedFile: editTestChart,
+		},
+		"with a change that would break the chart": {
+			command:      moveCommand,
+			input:        []string{"Nick Fury", "Black Widow"},
+			expectError:  true,
+			expectedFile: editTestChart,
+		},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "chart.txt")
+
+			if err := os.WriteFile(path, []byte(editTestChart), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			// Flags have to come before the filepath.
+			args := append([]string{}, tc.input[:len(tc.input)-2]...)
+			args = append(args, path)
+			args = append(args, tc.input[len(tc.input)-2:]...)
+
+			err := tc.command.run(args, &bytes.Buffer{})
+
+			if tc.expectError && err == nil {
+				t.Errorf("No error was returned when one was expected")
+			}
+
+			if !tc.expectError && err != nil {
+				t.Fatalf("An error '%s' was returned when none was expected", err)
+			}
+
+			saved, _ := os.ReadFile(path)
+
+			if string(saved) != tc.expectedFile {
+				t.Errorf("The saved file '%s' was not equal to the expected file '%s'", saved, tc.expectedFile)
+			}
+		})
+	}
+}
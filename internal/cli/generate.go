@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/lsg93/org-chart-parser/internal/generator"
+	"github.com/lsg93/org-chart-parser/orgchart"
+)
+
+type generateInput struct {
+	filepath string // blank writes to the output instead of a file
+	format   string
+	options  generator.Options
+}
+
+var (
+	errGenerateIncorrectArgumentAmount = errors.New("The generate command expects at most one argument (output filepath).")
+	errGenerateUnknownFormat           = errors.New("The generate command only supports the table and json formats.")
+)
+
+func runGenerateCommand(args []string, output io.Writer) error {
+	input, err := parseGenerateArguments(args)
+
+	if err != nil {
+		return err
+	}
+
+	chart, err := generator.Generate(input.options)
+
+	if err != nil {
+		return err
+	}
+
+	if input.filepath == "" {
+		return orgchart.Write(output, chart, input.format)
+	}
+
+	file, err := os.Create(input.filepath)
+
+	if err != nil {
+		return err
+	}
+
+	if err := orgchart.Write(file, chart, input.format); err != nil {
+		file.Close()
+		return err
+	}
+
+	// Buffered writes can still fail here, and the chart isn't saved unless they all made it.
+	return file.Close()
+}
+
+func parseGenerateArguments(args []string) (generateInput, error) {
+	defaults := generator.DefaultOptions()
+	distributions := make([]string, 0, len(generator.SpanDistributions))
+
+	for _, distribution := range generator.SpanDistributions {
+		distributions = append(distributions, string(distribution))
+	}
+
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	size := flags.Int("size", defaults.Size, "How many employees to generate.")
+	depth := flags.Int("depth", defaults.MaxDepth, "The maximum number of levels below the root - 0 means no limit.")
+	span := flags.Int("span", defaults.Span, "The average number of direct reports per manager.")
+	distribution := flags.String("distribution", string(defaults.Distribution), "How spans vary between managers ("+strings.Join(distributions, ", ")+").")
+	duplicates := flags.Float64("duplicates", defaults.DuplicateNameRate, "The chance (0 to 1) each employee reuses a name that's already been given out.")
+	attributes := flags.Bool("attributes", false, "Include titles and departments.")
+	cycles := flags.Int("cycles", 0, "How many management cycles to inject.")
+	orphans := flags.Int("orphans", 0, "How many employees to give a manager who doesn't exist.")
+	duplicateIds := flags.Int("duplicate-ids", 0, "How many extra rows to add with an ID that's already taken.")
+	seed := flags.Uint64("seed", defaults.Seed, "The random seed - the same seed and flags always give the same chart.")
+	format := flags.String("format", orgchart.ChartFormatTable, "The output format ("+strings.Join(orgchart.ChartFormats, ", ")+").")
+
+	if err := flags.Parse(args); err != nil {
+		return generateInput{}, err
+	}
+
+	args = flags.Args()
+
+	if len(args) > 1 {
+		return generateInput{}, errGenerateIncorrectArgumentAmount
+	}
+
+	// Checked up front, so an existing file isn't emptied before finding out the chart can't be written to it.
+	if !slices.Contains(orgchart.ChartFormats, *format) {
+		return generateInput{}, errGenerateUnknownFormat
+	}
+
+	input := generateInput{
+		format: *format,
+		options: generator.Options{
+			Size:              *size,
+			MaxDepth:          *depth,
+			Span:              *span,
+			Distribution:      generator.SpanDistribution(*distribution),
+			DuplicateNameRate: *duplicates,
+			Attributes:        *attributes,
+			Defects:           generator.Defects{Cycles: *cycles, Orphans: *orphans, DuplicateIds: *duplicateIds},
+			Seed:              *seed,
+		},
+	}
+
+	if len(args) == 1 {
+		if err := requireArguments(args, 1, errGenerateIncorrectArgumentAmount); err != nil {
+			return generateInput{}, err
+		}
+
+		input.filepath = args[0]
+	}
+
+	return input, nil
+}
@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lsg93/org-chart-parser/internal/generator"
+	"github.com/lsg93/org-chart-parser/internal/parser"
+)
+
+func TestParsingGenerateArguments(t *testing.T) {
+	defaults := generator.DefaultOptions()
+
+	type testCase struct {
+		input          []string
+		expectedResult generateInput
+	}
+
+	testCases := map[string]testCase{
+		"with no arguments": {
+			input:          []string{},
+			expectedResult: generateInput{format: parser.FormatTable, options: defaults},
+		},
+		"with an output file and flags": {
+			input: []string{"--size", "50", "--depth", "3", "--span", "4", "--distribution", "skewed", "--duplicates", "0.25", "--attributes", "--cycles", "1", "--orphans", "2", "--duplicate-ids", "3", "--seed", "99", "--format", "json", "chart.json"},
+			expectedResult: generateInput{
+				filepath: "chart.json",
+				format:   parser.FormatJSON,
+				options: generator.Options{
+					Size:              50,
+					MaxDepth:          3,
+					Span:              4,
+					Distribution:      generator.SkewedSpan,
+					DuplicateNameRate: 0.25,
+					Attributes:        true,
+					Defects:           generator.Defects{Cycles: 1, Orphans: 2, DuplicateIds: 3},
+					Seed:              99,
+				},
+			},
+		},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			result, err := parseGenerateArguments(tc.input)
+
+			if err != nil {
+				t.Fatalf("An error '%s' was returned when none was expected", err)
+			}
+
+			if result != tc.expectedResult {
+				t.Errorf("The struct %v returned was not equal to the expected value %v", result, tc.expectedResult)
+			}
+		})
+	}
+}
+
+func TestParsingInvalidGenerateArgumentsErrors(t *testing.T) {
+	type testCase struct {
+		input         []string
+		expectedError error
+	}
+
+	testCases := map[string]testCase{
+		"with two files": {
+			input:         []string{"one.txt", "two.txt"},
+			expectedError: errGenerateIncorrectArgumentAmount,
+		},
+		"with an unknown format": {
+			input:         []string{"--format", "yaml", "chart.yaml"},
+			expectedError: errGenerateUnknownFormat,
+		},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			if _, err := parseGenerateArguments(tc.input); err != tc.expectedError {
+				t.Errorf("The error '%v' was returned from validation, but it was not the expected error '%v'", err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestGenerateCommandLeavesTheFileAloneWithAnUnknownFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chart.txt")
+	existing := "| ID | Name | Manager ID |\n| 1 | Nick Fury | |\n"
+
+	if err := os.WriteFile(path, []byte(existing), 0o644); err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	if err := runGenerateCommand([]string{"--format", "yaml", path}, &bytes.Buffer{}); err != errGenerateUnknownFormat {
+		t.Errorf("The error '%v' was returned, but it was not the expected error '%v'", err, errGenerateUnknownFormat)
+	}
+
+	if data, _ := os.ReadFile(path); string(data) != existing {
+		t.Errorf("The received output '%s' was not equal to the expected output '%s'", data, existing)
+	}
+}
+
+func TestGenerateCommandWritesAParseableChart(t *testing.T) {
+	var output bytes.Buffer
+
+	if err := runGenerateCommand([]string{"--size", "25", "--seed", "3"}, &output); err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	p, _ := parser.NewOrganisationChartParser(strings.NewReader(output.String()))
+	chart, err := p.Parse()
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	if len(chart) != 25 {
+		t.Errorf("The generated chart had %d employees, expected 25.", len(chart))
+	}
+}
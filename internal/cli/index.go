@@ -0,0 +1,74 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lsg93/org-chart-parser/orgchart"
+)
+
+type indexInput struct {
+	filepath string
+}
+
+var (
+	errIndexIncorrectArgumentAmount = errors.New("The index command expects exactly one argument (filepath).")
+	errIndexDirectory               = errors.New("Only chart files can be indexed, not directories - index each snapshot in the directory on its own.")
+)
+
+// Saves the parsed chart and its tables next to the chart file. Every other command reads the index instead of parsing the chart,
+// for as long as the chart stays exactly as it was when it was indexed.
+func runIndexCommand(args []string, output io.Writer) error {
+	input, err := parseIndexArguments(args)
+
+	if err != nil {
+		return err
+	}
+
+	if info, err := os.Stat(input.filepath); err != nil {
+		return err
+	} else if info.IsDir() {
+		return errIndexDirectory
+	}
+
+	data, err := readFile(input.filepath)
+
+	if err != nil {
+		return err
+	}
+
+	var buffer bytes.Buffer
+	chart, err := orgchart.WriteIndex(&buffer, data)
+
+	if err != nil {
+		return err
+	}
+
+	if err := saveFile(orgchart.IndexPath(input.filepath), buffer.Bytes()); err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(output, "Indexed %d employees from %s into %s.\n", len(chart), input.filepath, orgchart.IndexPath(input.filepath))
+
+	return err
+}
+
+func parseIndexArguments(args []string) (indexInput, error) {
+	flags := flag.NewFlagSet("index", flag.ContinueOnError)
+
+	if err := flags.Parse(args); err != nil {
+		return indexInput{}, err
+	}
+
+	args = flags.Args()
+
+	if err := requireArguments(args, 1, errIndexIncorrectArgumentAmount); err != nil {
+		return indexInput{}, err
+	}
+
+	return indexInput{filepath: args[0]}, nil
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const indexTestChart = "| ID | Name | Manager ID | End Date |\n| 1 | Lawrence | | |\n| 2 | Adrian | 1 | |\n| 3 | Joshua | 2 | 2025-08-31 |\n"
+
+func writeIndexedChart(t *testing.T, contents string) string {
+	path := filepath.Join(t.TempDir(), "chart.txt")
+
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("An error '%s' occurred writing the test chart", err)
+	}
+
+	var output bytes.Buffer
+
+	if err := runIndexCommand([]string{path}, &output); err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	if expected := "Indexed 3 employees from " + path + " into " + path + ".idx.\n"; output.String() != expected {
+		t.Errorf("The received output '%s' was not equal to the expected output '%s'", output.String(), expected)
+	}
+
+	return path
+}
+
+func TestLoadingIndexedCharts(t *testing.T) {
+	type testCase struct {
+		update            string // replaces the chart after it's indexed, if given
+		date              time.Time
+		expectedHeadcount int
+		expectedTables    bool
+	}
+
+	testCases := map[string]testCase{
+		"with an up to date index":                    {date: time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC), expectedHeadcount: 3, expectedTables: true},
+		"on a date that leaves someone out":           {date: time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC), expectedHeadcount: 2},
+		"with a chart that's changed since":           {update: indexTestChart + "| 4 | Natalie | 1 | |\n", date: time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC), expectedHeadcount: 4},
+		"with a chart that was saved again unchanged": {update: indexTestChart, date: time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC), expectedHeadcount: 3, expectedTables: true},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			path := writeIndexedChart(t, indexTestChart)
+
+			if tc.update != "" {
+				if err := os.WriteFile(path, []byte(tc.update), 0o644); err != nil {
+					t.Fatalf("An error '%s' occurred writing the test chart", err)
+				}
+			}
+
+			loaded, err := loadChart(path, chartReadingInput{asOf: tc.date})
+
+			if err != nil {
+				t.Fatalf("An error '%s' was returned when none was expected", err)
+			}
+
+			if len(loaded.Chart) != tc.expectedHeadcount {
+				t.Errorf("The chart had %d employees, expected %d.", len(loaded.Chart), tc.expectedHeadcount)
+			}
+
+			if (len(loaded.Options()) > 0) != tc.expectedTables {
+				t.Errorf("The chart came with %d analyser options, but tables were expected: %t.", len(loaded.Options()), tc.expectedTables)
+			}
+		})
+	}
+}
+
+func TestIndexesInSnapshotDirectoriesAreNotSnapshots(t *testing.T) {
+	dir := filepath.Dir(writeIndexedChart(t, indexTestChart))
+
+	if err := os.Rename(filepath.Join(dir, "chart.txt"), filepath.Join(dir, "2025-01-01.txt")); err != nil {
+		t.Fatalf("An error '%s' occurred renaming the test chart", err)
+	}
+
+	if _, err := loadChart(dir, chartReadingInput{}); err != nil {
+		t.Errorf("An error '%s' was returned when none was expected", err)
+	}
+}
+
+func TestIndexingErrors(t *testing.T) {
+	type testCase struct {
+		args          []string
+		expectedError error
+	}
+
+	testCases := map[string]testCase{
+		"without a chart":  {args: []string{}, expectedError: errIndexIncorrectArgumentAmount},
+		"with a directory": {args: []string{t.TempDir()}, expectedError: errIndexDirectory},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			if err := runIndexCommand(tc.args, &bytes.Buffer{}); err != tc.expectedError {
+				t.Errorf("The error '%v' was returned, but it was not the expected error '%v'", err, tc.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io"
+
+	"github.com/lsg93/org-chart-parser/orgchart"
+)
+
+type reorgInput struct {
+	filepath       string
+	scriptFilepath string
+	nameMatching   nameMatchingInput
+	reading        chartReadingInput
+}
+
+var (
+	errReorgIncorrectArgumentAmount = errors.New("The reorg command expects exactly two arguments (filepath, script filepath).")
+)
+
+// Models a reorg script against the chart - the chart file itself is never changed.
+func runReorgCommand(args []string, output io.Writer) error {
+	input, err := parseReorgArguments(args)
+
+	if err != nil {
+		return err
+	}
+
+	loaded, err := loadChart(input.filepath, input.reading)
+
+	if err != nil {
+		return err
+	}
+
+	script, err := readFile(input.scriptFilepath)
+
+	if err != nil {
+		return err
+	}
+
+	operations, err := orgchart.ParseReorgScript(bytes.NewReader(script))
+
+	if err != nil {
+		return err
+	}
+
+	if err := writeChartHeading(output, loaded.Metadata); err != nil {
+		return err
+	}
+
+	analyser := loaded.Analyser(append(input.nameMatching.options(), orgchart.WithOutput(output))...)
+	return analyser.AnalyseReorg(operations)
+}
+
+func parseReorgArguments(args []string) (reorgInput, error) {
+	flags := flag.NewFlagSet("reorg", flag.ContinueOnError)
+	reading := addChartReadingFlags(flags)
+	nameMatching := addNameMatchingFlags(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return reorgInput{}, err
+	}
+
+	args = flags.Args()
+
+	if err := requireArguments(args, 2, errReorgIncorrectArgumentAmount); err != nil {
+		return reorgInput{}, err
+	}
+
+	return reorgInput{filepath: args[0], scriptFilepath: args[1], nameMatching: nameMatching(), reading: reading()}, nil
+}
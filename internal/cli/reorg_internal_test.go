@@ -0,0 +1,21 @@
+package cli
+
+import "testing"
+
+func TestParsingReorgArguments(t *testing.T) {
+	result, err := parseReorgArguments([]string{"--auto-select", "path/to/file.txt", "reorg.txt"})
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	expected := reorgInput{filepath: "path/to/file.txt", scriptFilepath: "reorg.txt", nameMatching: nameMatchingInput{autoSelect: true}}
+
+	if result != expected {
+		t.Errorf("The struct %v returned was not equal to the expected value %v", result, expected)
+	}
+
+	if _, err := parseReorgArguments([]string{"path/to/file.txt"}); err != errReorgIncorrectArgumentAmount {
+		t.Errorf("The error '%v' was returned from validation, but it was not the expected error '%v'", err, errReorgIncorrectArgumentAmount)
+	}
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"io"
+
+	"github.com/lsg93/org-chart-parser/orgchart"
+)
+
+type reportsInput struct {
+	filepath     string
+	employeeName string
+	nameMatching nameMatchingInput
+	depth        int
+	reading      chartReadingInput
+}
+
+var (
+	errReportsIncorrectArgumentAmount = errors.New("The reports command expects exactly two arguments (filepath, employee name).")
+)
+
+func runReportsCommand(args []string, output io.Writer) error {
+	input, err := parseReportsArguments(args)
+
+	if err != nil {
+		return err
+	}
+
+	loaded, err := loadChart(input.filepath, input.reading)
+
+	if err != nil {
+		return err
+	}
+
+	if err := writeChartHeading(output, loaded.Metadata); err != nil {
+		return err
+	}
+
+	analyser := loaded.Analyser(append(input.nameMatching.options(), orgchart.WithOutput(output))...)
+	return analyser.AnalyseReports(input.employeeName, input.depth)
+}
+
+func parseReportsArguments(args []string) (reportsInput, error) {
+	flags := flag.NewFlagSet("reports", flag.ContinueOnError)
+	reading := addChartReadingFlags(flags)
+	nameMatching := addNameMatchingFlags(flags)
+	depth := flags.Int("depth", 0, "How many levels of reports to include - 0 includes everyone.")
+	direct := flags.Bool("direct", false, "Only include direct reports - the same as --depth 1.")
+
+	if err := flags.Parse(args); err != nil {
+		return reportsInput{}, err
+	}
+
+	args = flags.Args()
+
+	if err := requireArguments(args, 2, errReportsIncorrectArgumentAmount); err != nil {
+		return reportsInput{}, err
+	}
+
+	input := reportsInput{filepath: args[0], employeeName: args[1], depth: *depth, nameMatching: nameMatching(), reading: reading()}
+
+	if *direct {
+		input.depth = 1
+	}
+
+	return input, nil
+}
@@ -0,0 +1,47 @@
+package cli
+
+import "testing"
+
+func TestParsingReportsArguments(t *testing.T) {
+	type testCase struct {
+		input          []string
+		expectedResult reportsInput
+	}
+
+	testCases := map[string]testCase{
+		"without a depth": {
+			input:          []string{"path/to/file.txt", "Joshua"},
+			expectedResult: reportsInput{filepath: "path/to/file.txt", employeeName: "Joshua"},
+		},
+		"with a depth": {
+			input:          []string{"--depth", "2", "path/to/file.txt", "Joshua"},
+			expectedResult: reportsInput{filepath: "path/to/file.txt", employeeName: "Joshua", depth: 2},
+		},
+		"with direct reports only": {
+			input:          []string{"--direct", "path/to/file.txt", "Joshua"},
+			expectedResult: reportsInput{filepath: "path/to/file.txt", employeeName: "Joshua", depth: 1},
+		},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			result, err := parseReportsArguments(tc.input)
+
+			if err != nil {
+				t.Fatalf("An error '%s' was returned when none was expected", err)
+			}
+
+			if result != tc.expectedResult {
+				t.Errorf("The struct %v returned was not equal to the expected value %v", result, tc.expectedResult)
+			}
+		})
+	}
+}
+
+func TestParsingInvalidReportsArgumentsErrors(t *testing.T) {
+	_, err := parseReportsArguments([]string{"--depth", "2", "path/to/file.txt"})
+
+	if err != errReportsIncorrectArgumentAmount {
+		t.Errorf("The error '%v' was returned from validation, but it was not the expected error '%v'", err, errReportsIncorrectArgumentAmount)
+	}
+}
@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/lsg93/org-chart-parser/internal/server"
+	"github.com/lsg93/org-chart-parser/internal/watch"
+	"github.com/lsg93/org-chart-parser/orgchart"
+)
+
+type serveInput struct {
+	filepaths    []string
+	addr         string
+	timeout      time.Duration
+	poll         time.Duration // 0 means charts aren't reloaded
+	nameMatching nameMatchingInput
+	reading      chartReadingInput
+}
+
+var (
+	errServeIncorrectArgumentAmount = errors.New("The serve command expects at least one argument (chart filepath).")
+	errServeInvalidTimeout          = errors.New("The request timeout (--timeout) must be more than zero, e.g. 10s.")
+	errServeInvalidPollInterval     = errors.New("The polling interval (--poll) can't be negative - use 0 to stop charts being reloaded.")
+)
+
+// Serves the charts until interrupted - Ctrl+C (or SIGTERM) lets requests in flight finish before stopping.
+// Chart files are checked for changes while serving, and reloaded when they change.
+func runServeCommand(args []string, output io.Writer) error {
+	input, err := parseServeArguments(args)
+
+	if err != nil {
+		return err
+	}
+
+	charts := make([]server.Chart, 0, len(input.filepaths))
+
+	for _, path := range input.filepaths {
+		loaded, err := loadChart(path, input.reading)
+
+		if err != nil {
+			return err
+		}
+
+		charts = append(charts, servedChart(path, loaded))
+	}
+
+	chartServer, err := server.NewServer(charts, input.timeout, input.nameMatching.options()...)
+
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if _, err := fmt.Fprintf(output, "Serving %d charts on http://%s - press Ctrl+C to stop.\n", len(charts), input.addr); err != nil {
+		return err
+	}
+
+	if input.poll > 0 {
+		logger := log.New(output, "", log.LstdFlags)
+
+		go watch.Poll(ctx, input.poll, input.filepaths, func(path string) {
+			reloadServedChart(ctx, chartServer, path, input.reading, logger)
+		})
+	}
+
+	return chartServer.ListenAndServe(ctx, input.addr)
+}
+
+// A chart that can't be read or doesn't validate any more is logged and otherwise ignored, so the last good version stays up
+// until the file is fixed. A reload that's still going when the server stops is dropped, as there's nothing left to serve it.
+func reloadServedChart(ctx context.Context, chartServer *server.Server, path string, reading chartReadingInput, logger *log.Logger) {
+	loaded, err := loadChartContext(ctx, path, reading)
+
+	if err == nil {
+		err = chartServer.Reload(servedChart(path, loaded))
+	}
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	if err != nil {
+		logger.Printf("%s changed, but couldn't be reloaded - still serving the previous version.\n%s", path, err)
+		return
+	}
+
+	logger.Printf("Reloaded %s.", path)
+}
+
+func servedChart(path string, loaded orgchart.LoadedChart) server.Chart {
+	return server.Chart{Name: chartName(path), Chart: loaded.Chart, Metadata: loaded.Metadata, Options: loaded.Options()}
+}
+
+func parseServeArguments(args []string) (serveInput, error) {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	addr := flags.String("addr", "localhost:8080", "The address to listen on.")
+	timeout := flags.Duration("timeout", server.DefaultTimeout, "How long a request can take before it's abandoned.")
+	poll := flags.Duration("poll", watch.DefaultInterval, "How often to check the charts for changes, reloading any that have changed. 0 turns reloading off.")
+	nameMatching := addNameMatchingFlags(flags)
+	guessVacancies := addGuessVacanciesFlag(flags)
+
+	if err := flags.Parse(args); err != nil {
+		return serveInput{}, err
+	}
+
+	args = flags.Args()
+
+	if len(args) == 0 {
+		return serveInput{}, errServeIncorrectArgumentAmount
+	}
+
+	if err := requireArguments(args, len(args), errServeIncorrectArgumentAmount); err != nil {
+		return serveInput{}, err
+	}
+
+	if *timeout <= 0 {
+		return serveInput{}, errServeInvalidTimeout
+	}
+
+	if *poll < 0 {
+		return serveInput{}, errServeInvalidPollInterval
+	}
+
+	return serveInput{filepaths: args, addr: *addr, timeout: *timeout, poll: *poll, nameMatching: nameMatching(), reading: chartReadingInput{guessVacancies: *guessVacancies}}, nil
+}
+
+// Charts are served under their file name without the extension, so charts/avengers.txt is /charts/avengers.
+func chartName(path string) string {
+	base := filepath.Base(filepath.Clean(path))
+
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
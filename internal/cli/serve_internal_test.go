@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+	"github.com/lsg93/org-chart-parser/internal/server"
+)
+
+func TestParsingServeArguments(t *testing.T) {
+	type testCase struct {
+		input          []string
+		expectedResult serveInput
+	}
+
+	testCases := map[string]testCase{
+		"with defaults": {
+			input:          []string{"avengers.txt"},
+			expectedResult: serveInput{filepaths: []string{"avengers.txt"}, addr: "localhost:8080", timeout: 10 * time.Second, poll: 2 * time.Second},
+		},
+		"with flags and several charts": {
+			input:          []string{"--addr", ":9000", "--timeout", "2s", "--poll", "0", "--strict", "avengers.txt", "snapshots/"},
+			expectedResult: serveInput{filepaths: []string{"avengers.txt", "snapshots/"}, addr: ":9000", timeout: 2 * time.Second, nameMatching: nameMatchingInput{strict: true}},
+		},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			result, err := parseServeArguments(tc.input)
+
+			if err != nil {
+				t.Fatalf("An error '%s' was returned when none was expected", err)
+			}
+
+			if !reflect.DeepEqual(result, tc.expectedResult) {
+				t.Errorf("The struct %v returned was not equal to the expected value %v", result, tc.expectedResult)
+			}
+		})
+	}
+}
+
+func TestParsingInvalidServeArgumentsErrors(t *testing.T) {
+	type testCase struct {
+		input         []string
+		expectedError error
+	}
+
+	testCases := map[string]testCase{
+		"without a chart":               {input: []string{"--addr", ":9000"}, expectedError: errServeIncorrectArgumentAmount},
+		"with a zero timeout":           {input: []string{"--timeout", "0s", "avengers.txt"}, expectedError: errServeInvalidTimeout},
+		"with a negative poll interval": {input: []string{"--poll", "-1s", "avengers.txt"}, expectedError: errServeInvalidPollInterval},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			if _, err := parseServeArguments(tc.input); err != tc.expectedError {
+				t.Errorf("The error '%v' was returned from validation, but it was not the expected error '%v'", err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestChartNamesComeFromFileNames(t *testing.T) {
+	for path, expected := range map[string]string{"charts/avengers.txt": "avengers", "snapshots/": "snapshots", "example.md": "example"} {
+		if name := chartName(path); name != expected {
+			t.Errorf("The name '%s' for %s was not the expected name '%s'", name, path, expected)
+		}
+	}
+}
+
+func TestReloadingServedCharts(t *testing.T) {
+	type testCase struct {
+		updated        string
+		expectedOutput string
+	}
+
+	testCases := map[string]testCase{
+		"with a valid chart":            {updated: "| ID | Name | Manager ID |\n| 1 | Nick Fury | |\n| 2 | Maria Hill | 1 |", expectedOutput: "Reloaded"},
+		"with a chart that won't parse": {updated: "| ID | Name | Manager ID |\n| one | Nick Fury | |", expectedOutput: "couldn't be reloaded - still serving the previous version"},
+		"with a management cycle":       {updated: "| ID | Name | Manager ID |\n| 1 | Nick Fury | 2 |\n| 2 | Maria Hill | 1 |", expectedOutput: "isn't valid"},
+		"with a broken chain":           {updated: "| ID | Name | Manager ID |\n| 1 | Nick Fury | |\n| 2 | Maria Hill | 99 |", expectedOutput: "isn't valid"},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "avengers.txt")
+			chartServer, err := server.NewServer([]server.Chart{{Name: "avengers", Chart: model.OrganisationChart{{Id: 1, Name: "Nick Fury"}}}}, server.DefaultTimeout)
+
+			if err != nil {
+				t.Fatalf("An error '%s' was returned when none was expected", err)
+			}
+
+			if err := os.WriteFile(path, []byte(tc.updated), 0o644); err != nil {
+				t.Fatalf("An error '%s' was returned when none was expected", err)
+			}
+
+			var output bytes.Buffer
+			reloadServedChart(context.Background(), chartServer, path, chartReadingInput{}, log.New(&output, "", 0))
+
+			if !strings.Contains(output.String(), tc.expectedOutput) {
+				t.Errorf("The received output '%s' did not contain the expected output '%s'", output.String(), tc.expectedOutput)
+			}
+		})
+	}
+}
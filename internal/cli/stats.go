@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/lsg93/org-chart-parser/internal/watch"
+	"github.com/lsg93/org-chart-parser/orgchart"
+)
+
+type statsInput struct {
+	filepath string
+	reading  chartReadingInput
+	watch    bool
+}
+
+var (
+	errStatsIncorrectArgumentAmount = errors.New("The stats command expects exactly one argument (filepath).")
+)
+
+func runStatsCommand(args []string, output io.Writer) error {
+	input, err := parseStatsArguments(args)
+
+	if err != nil {
+		return err
+	}
+
+	if err := writeStats(context.Background(), input, output, false); err != nil {
+		return err
+	}
+
+	if !input.watch {
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if _, err := fmt.Fprintf(output, "\nWatching %s for changes - press Ctrl+C to stop.\n", input.filepath); err != nil {
+		return err
+	}
+
+	watch.Poll(ctx, watch.DefaultInterval, []string{input.filepath}, func(path string) {
+		restatChangedChart(ctx, input, output)
+	})
+
+	return nil
+}
+
+// The new stats are only shown once the chart has loaded and validated, so a broken save leaves the last good stats on screen
+// with the problems underneath. Nothing is shown if watching stopped part way through.
+func restatChangedChart(ctx context.Context, input statsInput, output io.Writer) {
+	var stats bytes.Buffer
+
+	err := writeStats(ctx, input, &stats, true)
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	if err != nil {
+		fmt.Fprintf(output, "\n%s changed, but couldn't be reloaded - the stats above are still for the previous version.\n%s\n", input.filepath, err)
+		return
+	}
+
+	fmt.Fprintf(output, "\n%s changed at %s:\n%s", input.filepath, time.Now().Format(time.TimeOnly), stats.String())
+}
+
+func writeStats(ctx context.Context, input statsInput, output io.Writer, validate bool) error {
+	loaded, err := loadChartContext(ctx, input.filepath, input.reading)
+
+	if err != nil {
+		return err
+	}
+
+	analyser := loaded.Analyser(orgchart.WithOutput(output))
+
+	if validate {
+		if err := analyser.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := writeChartHeading(output, loaded.Metadata); err != nil {
+		return err
+	}
+
+	return analyser.AnalyseStats()
+}
+
+func parseStatsArguments(args []string) (statsInput, error) {
+	flags := flag.NewFlagSet("stats", flag.ContinueOnError)
+	reading := addChartReadingFlags(flags)
+	watch := flags.Bool("watch", false, "Keep watching the chart, showing the stats again whenever it changes.")
+
+	if err := flags.Parse(args); err != nil {
+		return statsInput{}, err
+	}
+
+	args = flags.Args()
+
+	if err := requireArguments(args, 1, errStatsIncorrectArgumentAmount); err != nil {
+		return statsInput{}, err
+	}
+
+	return statsInput{filepath: args[0], reading: reading(), watch: *watch}, nil
+}
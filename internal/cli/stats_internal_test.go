@@ -0,0 +1,111 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParsingStatsArguments(t *testing.T) {
+	result, err := parseStatsArguments([]string{"path/to/file.txt"})
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	if result.filepath != "path/to/file.txt" {
+		t.Errorf("The filepath '%s' was not the expected filepath.", result.filepath)
+	}
+
+	if result, _ := parseStatsArguments([]string{"--watch", "path/to/file.txt"}); !result.watch {
+		t.Errorf("The chart wasn't watched even though --watch was given.")
+	}
+
+	if _, err := parseStatsArguments([]string{}); err != errStatsIncorrectArgumentAmount {
+		t.Errorf("The error '%v' was returned from validation, but it was not the expected error '%v'", err, errStatsIncorrectArgumentAmount)
+	}
+}
+
+func TestShowingStatsForChangedCharts(t *testing.T) {
+	type testCase struct {
+		updated        string
+		expectedOutput string
+	}
+
+	testCases := map[string]testCase{
+		"with a valid chart":      {updated: "| ID | Name | Manager ID |\n| 1 | Nick Fury | |\n| 2 | Maria Hill | 1 |", expectedOutput: "Headcount: 2"},
+		"with a management cycle": {updated: "| ID | Name | Manager ID |\n| 1 | Nick Fury | 2 |\n| 2 | Maria Hill | 1 |", expectedOutput: "the stats above are still for the previous version"},
+		"with a broken chain":     {updated: "| ID | Name | Manager ID |\n| 1 | Nick Fury | |\n| 2 | Maria Hill | 99 |", expectedOutput: "the stats above are still for the previous version"},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "avengers.txt")
+
+			if err := os.WriteFile(path, []byte(tc.updated), 0o644); err != nil {
+				t.Fatalf("An error '%s' was returned when none was expected", err)
+			}
+
+			var output bytes.Buffer
+			restatChangedChart(context.Background(), statsInput{filepath: path, watch: true}, &output)
+
+			if !strings.Contains(output.String(), tc.expectedOutput) {
+				t.Errorf("The received output '%s' did not contain the expected output '%s'", output.String(), tc.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestChangesAfterWatchingStopsAreIgnored(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "avengers.txt")
+
+	if err := os.WriteFile(path, []byte("| ID | Name | Manager ID |\n| 1 | Nick Fury | |"), 0o644); err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var output bytes.Buffer
+	restatChangedChart(ctx, statsInput{filepath: path, watch: true}, &output)
+
+	if output.Len() > 0 {
+		t.Errorf("The received output '%s' was not equal to the expected output ''", output.String())
+	}
+}
+
+func TestVacanciesAreOnlyGuessedFromNamesWhenAsked(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "avengers.txt")
+	chart := "| ID | Name | Manager ID |\n| 1 | Nick Fury | |\n| 2 | TBH - Agent | 1 |\n| 3 | Maria Hill | 1 |"
+
+	if err := os.WriteFile(path, []byte(chart), 0o644); err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	type testCase struct {
+		args           []string
+		expectedOutput string
+	}
+
+	testCases := map[string]testCase{
+		"without --guess-vacancies": {args: []string{path}, expectedOutput: "Headcount: 3\n"},
+		"with --guess-vacancies":    {args: []string{"--guess-vacancies", path}, expectedOutput: "Headcount: 2 (3 positions, 1 vacant)\n"},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			var output bytes.Buffer
+
+			if err := runStatsCommand(tc.args, &output); err != nil {
+				t.Fatalf("An error '%s' was returned when none was expected", err)
+			}
+
+			if !strings.HasPrefix(output.String(), tc.expectedOutput) {
+				t.Errorf("The received output '%s' was not equal to the expected output '%s'", output.String(), tc.expectedOutput)
+			}
+		})
+	}
+}
@@ -0,0 +1,311 @@
+// Package generator builds synthetic organisation charts for testing and benchmarking.
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+// How the number of direct reports is picked for each manager.
+type SpanDistribution string
+
+const (
+	FixedSpan   SpanDistribution = "fixed"   // every manager gets exactly Span reports
+	UniformSpan SpanDistribution = "uniform" // anywhere between 1 and 2 * Span - 1, averaging Span
+	SkewedSpan  SpanDistribution = "skewed"  // mostly small teams with the occasional very large one, averaging roughly Span
+)
+
+var SpanDistributions = []SpanDistribution{FixedSpan, UniformSpan, SkewedSpan}
+
+var (
+	errGeneratorInvalidSize         = errors.New("The chart size must be at least 1.")
+	errGeneratorInvalidSpan         = errors.New("The span of control must be at least 1.")
+	errGeneratorInvalidDepth        = errors.New("The maximum depth can't be negative.")
+	errGeneratorUnknownDistribution = errors.New("The span distribution provided is not supported - use fixed, uniform or skewed.")
+	errGeneratorInvalidRate         = errors.New("The duplicate name rate must be between 0 and 1.")
+	errGeneratorInvalidDefects      = errors.New("The number of defects can't be negative.")
+	errGeneratorTooManyDefects      = errors.New("The chart isn't big enough for the number of defects requested.")
+)
+
+// Problems to deliberately inject, so that validation and error handling can be exercised.
+type Defects struct {
+	Cycles       int // managers who end up reporting to one of their own reports
+	Orphans      int // employees whose manager ID doesn't exist in the chart
+	DuplicateIds int // extra rows that reuse someone else's ID - they still count towards the size
+}
+
+type Options struct {
+	Size              int
+	MaxDepth          int // 0 means no limit
+	Span              int
+	Distribution      SpanDistribution
+	DuplicateNameRate float64 // the chance each employee reuses a name that's already been given out
+	Attributes        bool    // fill in titles and departments
+	Defects           Defects
+	Seed              uint64 // the same seed and options always give the same chart
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Size:         100,
+		Span:         5,
+		Distribution: UniformSpan,
+		Seed:         1,
+	}
+}
+
+type generator struct {
+	options   Options
+	positions int // rows in the tree itself - the size, less the rows added for duplicate IDs
+	random    *rand.Rand
+	chart     model.OrganisationChart
+	depths    []int // depth of each employee in the chart, by index
+	names     []string
+	counts    map[string]int
+}
+
+// Builds a chart breadth first from a single root with ID 1, so IDs increase level by level.
+func Generate(options Options) (model.OrganisationChart, error) {
+	if err := validateOptions(options); err != nil {
+		return nil, err
+	}
+
+	g := &generator{
+		options:   options,
+		positions: options.Size - options.Defects.DuplicateIds,
+		random:    rand.New(rand.NewPCG(options.Seed, options.Seed)),
+		chart:     make(model.OrganisationChart, 0, options.Size),
+		depths:    make([]int, 0, options.Size),
+		counts:    make(map[string]int),
+	}
+
+	g.chart = append(g.chart, g.employee(1, 0, 0))
+	g.depths = append(g.depths, 0)
+
+	// Managers waiting to be given their reports, in the order they were added.
+	queue := []int{0}
+
+	for len(queue) > 0 && len(g.chart) < g.positions {
+		manager := queue[0]
+		queue = queue[1:]
+
+		if g.canManage(g.depths[manager]) {
+			queue = append(queue, g.addReports(manager, g.span())...)
+		}
+	}
+
+	// The depth limit was reached before the size - go round everyone who can still manage, one extra report at a time.
+	if len(g.chart) < g.positions {
+		managers := make([]int, 0)
+
+		for i, depth := range g.depths {
+			if g.canManage(depth) {
+				managers = append(managers, i)
+			}
+		}
+
+		for i := 0; len(g.chart) < g.positions; i++ {
+			g.addReports(managers[i%len(managers)], 1)
+		}
+	}
+
+	if err := g.injectDefects(); err != nil {
+		return nil, err
+	}
+
+	return g.chart, nil
+}
+
+func validateOptions(options Options) error {
+	switch {
+	case options.Size < 1:
+		return errGeneratorInvalidSize
+	case options.Span < 1:
+		return errGeneratorInvalidSpan
+	case options.MaxDepth < 0:
+		return errGeneratorInvalidDepth
+	case options.DuplicateNameRate < 0 || options.DuplicateNameRate > 1:
+		return errGeneratorInvalidRate
+	}
+
+	switch options.Distribution {
+	case FixedSpan, UniformSpan, SkewedSpan:
+	default:
+		return errGeneratorUnknownDistribution
+	}
+
+	defects := options.Defects
+
+	if defects.Cycles < 0 || defects.Orphans < 0 || defects.DuplicateIds < 0 {
+		return errGeneratorInvalidDefects
+	}
+
+	// Each cycle needs a manager and a report, and each orphan needs an employee who isn't the root.
+	// Each duplicate ID needs an extra row, as well as an employee who isn't the root to share it with.
+	if 2*defects.Cycles+defects.Orphans+2*defects.DuplicateIds > options.Size-1 {
+		return fmt.Errorf("%w %d employees can't hold %d cycles, %d orphans and %d duplicate IDs.", errGeneratorTooManyDefects, options.Size, defects.Cycles, defects.Orphans, defects.DuplicateIds)
+	}
+
+	return nil
+}
+
+func (g *generator) canManage(depth int) bool {
+	return g.options.MaxDepth == 0 || depth < g.options.MaxDepth
+}
+
+// Adds up to count reports under the manager, stopping at the requested size. Returns the indexes of the new employees.
+func (g *generator) addReports(manager int, count int) []int {
+	added := make([]int, 0, count)
+	depth := g.depths[manager] + 1
+
+	for range count {
+		if len(g.chart) == g.positions {
+			break
+		}
+
+		added = append(added, len(g.chart))
+		g.chart = append(g.chart, g.employee(len(g.chart)+1, g.chart[manager].Id, depth))
+		g.depths = append(g.depths, depth)
+	}
+
+	return added
+}
+
+func (g *generator) span() int {
+	span := g.options.Span
+
+	switch g.options.Distribution {
+	case UniformSpan:
+		return 1 + g.random.IntN(2*span-1)
+	case SkewedSpan:
+		// An exponential distribution - the mean is the requested span, but the tail is long.
+		return 1 + int(g.random.ExpFloat64()*float64(span-1)+0.5)
+	default:
+		return span
+	}
+}
+
+var firstNames = []string{
+	"Ada", "Alan", "Barbara", "Charles", "Dorothy", "Edsger", "Frances", "Grace", "Hedy", "Ivan",
+	"Jean", "Ken", "Katherine", "Linus", "Margaret", "Niklaus", "Radia", "Shafi", "Tim", "Yukihiro",
+}
+
+var lastNames = []string{
+	"Allen", "Babbage", "Cerf", "Dijkstra", "Estrin", "Floyd", "Goldwasser", "Hopper", "Johnson", "Knuth",
+	"Lamarr", "Liskov", "Lovelace", "Perlman", "Ritchie", "Sutherland", "Thompson", "Torvalds", "Turing", "Wirth",
+}
+
+var titles = []string{"Director", "Manager", "Team Lead", "Engineer", "Analyst", "Designer"}
+
+var departments = []string{"Engineering", "Finance", "Operations", "People", "Product", "Sales"}
+
+func (g *generator) employee(id int, managerId int, depth int) model.Employee {
+	employee := model.Employee{Id: id, Name: g.name(), ManagerId: managerId}
+
+	if g.options.Attributes {
+		employee.Title = titles[min(depth, len(titles)-1)]
+		employee.Department = departments[g.random.IntN(len(departments))]
+	}
+
+	return employee
+}
+
+// Names are unique unless the duplicate name rate says otherwise - once a combination has been used, later ones get a number added.
+func (g *generator) name() string {
+	if len(g.names) > 0 && g.random.Float64() < g.options.DuplicateNameRate {
+		return g.names[g.random.IntN(len(g.names))]
+	}
+
+	name := firstNames[g.random.IntN(len(firstNames))] + " " + lastNames[g.random.IntN(len(lastNames))]
+	g.counts[name]++
+
+	if g.counts[name] > 1 {
+		name = fmt.Sprintf("%s %d", name, g.counts[name])
+	}
+
+	g.names = append(g.names, name)
+
+	return name
+}
+
+// Picks distinct employees for each defect so that one doesn't undo another.
+// The root (index 0) is never touched, so the chart always keeps a sensible top.
+func (g *generator) injectDefects() error {
+	defects := g.options.Defects
+	chart := g.chart
+	taken := map[int]bool{0: true}
+
+	pick := func(candidates []int) (int, bool) {
+		available := make([]int, 0, len(candidates))
+
+		for _, i := range candidates {
+			if !taken[i] {
+				available = append(available, i)
+			}
+		}
+
+		if len(available) == 0 {
+			return 0, false
+		}
+
+		i := available[g.random.IntN(len(available))]
+		taken[i] = true
+
+		return i, true
+	}
+
+	reports := make(map[int][]int)
+
+	for i, employee := range chart {
+		reports[employee.ManagerId] = append(reports[employee.ManagerId], i)
+	}
+
+	for range defects.Cycles {
+		managers := make([]int, 0)
+
+		for i, employee := range chart {
+			if i > 0 && !taken[i] && slices.ContainsFunc(reports[employee.Id], func(report int) bool { return !taken[report] }) {
+				managers = append(managers, i)
+			}
+		}
+
+		manager, ok := pick(managers)
+
+		if !ok {
+			return fmt.Errorf("%w There aren't enough managers below the root to make %d cycles.", errGeneratorTooManyDefects, defects.Cycles)
+		}
+
+		report, _ := pick(reports[chart[manager].Id])
+
+		// The manager now reports to their own report, cutting the pair off from the rest of the chart.
+		chart[manager].ManagerId = chart[report].Id
+	}
+
+	everyone := make([]int, len(chart))
+
+	for i := range chart {
+		everyone[i] = i
+	}
+
+	for range defects.Orphans {
+		i, _ := pick(everyone)
+
+		// IDs only go up to the size of the chart, so anything beyond it points at nobody.
+		chart[i].ManagerId = len(chart) + 1 + g.random.IntN(len(chart))
+	}
+
+	// A new row that shares someone's ID and manager, rather than taking over an existing row's ID and orphaning its reports.
+	for range defects.DuplicateIds {
+		i, _ := pick(everyone)
+		duplicate := g.employee(chart[i].Id, chart[i].ManagerId, g.depths[i])
+
+		g.chart = append(g.chart, duplicate)
+		g.depths = append(g.depths, g.depths[i])
+	}
+
+	return nil
+}
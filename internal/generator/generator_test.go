@@ -0,0 +1,262 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+	"github.com/lsg93/org-chart-parser/internal/parser"
+)
+
+func generate(t *testing.T, options Options) model.OrganisationChart {
+	chart, err := Generate(options)
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	return chart
+}
+
+func depthOf(chart model.OrganisationChart, employee model.Employee) int {
+	managers := make(map[int]int, len(chart))
+
+	for _, e := range chart {
+		managers[e.Id] = e.ManagerId
+	}
+
+	depth := 0
+
+	for id := employee.ManagerId; id != 0; id = managers[id] {
+		depth++
+	}
+
+	return depth
+}
+
+func TestGeneratesChartsOfTheRequestedShape(t *testing.T) {
+	type testCase struct {
+		options Options
+		check   func(t *testing.T, chart model.OrganisationChart)
+	}
+
+	testCases := map[string]testCase{
+		"with a fixed span": {
+			options: Options{Size: 13, Span: 3, Distribution: FixedSpan, Seed: 1},
+			check: func(t *testing.T, chart model.OrganisationChart) {
+				// A perfect tree - the root, 3 managers and 9 leaves.
+				for i, employee := range chart {
+					expected := 0
+
+					if i > 0 {
+						expected = (i-1)/3 + 1
+					}
+
+					if employee.Id != i+1 || employee.ManagerId != expected {
+						t.Errorf("Employee %d had ID %d and manager ID %d, expected manager ID %d.", i, employee.Id, employee.ManagerId, expected)
+					}
+				}
+			},
+		},
+		"with a maximum depth": {
+			options: Options{Size: 200, Span: 2, MaxDepth: 2, Distribution: UniformSpan, Seed: 7},
+			check: func(t *testing.T, chart model.OrganisationChart) {
+				for _, employee := range chart {
+					if depth := depthOf(chart, employee); depth > 2 {
+						t.Errorf("%s was at depth %d, deeper than the maximum of 2.", employee.Name, depth)
+					}
+				}
+			},
+		},
+		"with no duplicate names": {
+			options: Options{Size: 1000, Span: 8, Distribution: SkewedSpan, Seed: 3},
+			check: func(t *testing.T, chart model.OrganisationChart) {
+				seen := make(map[string]bool)
+
+				for _, employee := range chart {
+					if seen[employee.Name] {
+						t.Errorf("The name '%s' was used more than once.", employee.Name)
+					}
+
+					seen[employee.Name] = true
+				}
+			},
+		},
+		"with duplicate names": {
+			options: Options{Size: 1000, Span: 8, Distribution: UniformSpan, DuplicateNameRate: 0.5, Seed: 3},
+			check: func(t *testing.T, chart model.OrganisationChart) {
+				names := make(map[string]bool)
+
+				for _, employee := range chart {
+					names[employee.Name] = true
+				}
+
+				// Roughly half the employees should have reused a name.
+				if len(names) < 400 || len(names) > 600 {
+					t.Errorf("There were %d distinct names, expected around 500.", len(names))
+				}
+			},
+		},
+		"with attributes": {
+			options: Options{Size: 20, Span: 3, Distribution: FixedSpan, Attributes: true, Seed: 1},
+			check: func(t *testing.T, chart model.OrganisationChart) {
+				for _, employee := range chart {
+					if employee.Title == "" || employee.Department == "" {
+						t.Errorf("%s was missing a title or department.", employee.Name)
+					}
+				}
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			chart := generate(t, tc.options)
+
+			if len(chart) != tc.options.Size {
+				t.Fatalf("The chart had %d employees, expected %d.", len(chart), tc.options.Size)
+			}
+
+			tc.check(t, chart)
+		})
+	}
+}
+
+func TestGeneratingIsReproducible(t *testing.T) {
+	options := Options{Size: 500, Span: 4, Distribution: SkewedSpan, DuplicateNameRate: 0.1, Attributes: true, Seed: 42}
+
+	if !reflect.DeepEqual(generate(t, options), generate(t, options)) {
+		t.Errorf("The same seed produced two different charts.")
+	}
+
+	options.Seed = 43
+
+	if reflect.DeepEqual(generate(t, Options{Size: 500, Span: 4, Distribution: SkewedSpan, Seed: 42}), generate(t, options)) {
+		t.Errorf("Different seeds produced the same chart.")
+	}
+}
+
+func TestInjectsDefects(t *testing.T) {
+	options := Options{Size: 300, Span: 4, Distribution: UniformSpan, Seed: 9, Defects: Defects{Cycles: 2, Orphans: 3, DuplicateIds: 4}}
+	chart := generate(t, options)
+
+	ids := make(map[int]int)
+	managers := make(map[int]int)
+
+	for _, employee := range chart {
+		ids[employee.Id]++
+		managers[employee.Id] = employee.ManagerId
+	}
+
+	duplicates := 0
+
+	for _, count := range ids {
+		duplicates += count - 1
+	}
+
+	if duplicates != options.Defects.DuplicateIds {
+		t.Errorf("Found %d duplicate IDs, expected %d.", duplicates, options.Defects.DuplicateIds)
+	}
+
+	if orphans := countOrphans(chart); orphans != options.Defects.Orphans {
+		t.Errorf("Found %d orphans, expected %d.", orphans, options.Defects.Orphans)
+	}
+
+	cycles := 0
+
+	for _, employee := range chart {
+		// Every injected cycle is a pair managing each other.
+		if manager := employee.ManagerId; manager != 0 && managers[manager] == employee.Id && employee.Id < manager {
+			cycles++
+		}
+	}
+
+	if cycles != options.Defects.Cycles {
+		t.Errorf("Found %d cycles, expected %d.", cycles, options.Defects.Cycles)
+	}
+}
+
+func TestDuplicateIdsDontOrphanAnyone(t *testing.T) {
+	// A few seeds, so that some of the IDs shared are managers' as well as leaves'.
+	for seed := range uint64(5) {
+		options := Options{Size: 300, Span: 4, Distribution: UniformSpan, Seed: seed, Defects: Defects{Orphans: 0, DuplicateIds: 20}}
+		chart := generate(t, options)
+
+		if len(chart) != options.Size {
+			t.Errorf("The chart for seed %d had %d rows, expected %d.", seed, len(chart), options.Size)
+		}
+
+		if orphans := countOrphans(chart); orphans != 0 {
+			t.Errorf("Found %d orphans for seed %d, when duplicate IDs shouldn't leave anyone without a manager.", orphans, seed)
+		}
+	}
+}
+
+// Employees whose manager ID isn't anyone's ID.
+func countOrphans(chart model.OrganisationChart) int {
+	ids := make(map[int]bool, len(chart))
+
+	for _, employee := range chart {
+		ids[employee.Id] = true
+	}
+
+	orphans := 0
+
+	for _, employee := range chart {
+		if employee.ManagerId != 0 && !ids[employee.ManagerId] {
+			orphans++
+		}
+	}
+
+	return orphans
+}
+
+func TestGeneratedChartsRoundTripThroughTheParser(t *testing.T) {
+	chart := generate(t, Options{Size: 250, Span: 5, Distribution: UniformSpan, DuplicateNameRate: 0.2, Attributes: true, Seed: 5})
+
+	var buffer bytes.Buffer
+	writer, _ := parser.NewOrganisationChartWriter(&buffer, parser.FormatTable)
+
+	if err := writer.Write(chart); err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	p, _ := parser.NewOrganisationChartParser(&buffer)
+	result, err := p.Parse()
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	if !reflect.DeepEqual(result, chart) {
+		t.Errorf("The parsed chart was not the same as the generated chart.")
+	}
+}
+
+func TestRejectsInvalidOptions(t *testing.T) {
+	type testCase struct {
+		options       Options
+		expectedError error
+	}
+
+	testCases := map[string]testCase{
+		"with no employees":            {options: Options{Size: 0, Span: 3, Distribution: FixedSpan}, expectedError: errGeneratorInvalidSize},
+		"with no span":                 {options: Options{Size: 10, Span: 0, Distribution: FixedSpan}, expectedError: errGeneratorInvalidSpan},
+		"with a negative depth":        {options: Options{Size: 10, Span: 3, MaxDepth: -1, Distribution: FixedSpan}, expectedError: errGeneratorInvalidDepth},
+		"with an unknown distribution": {options: Options{Size: 10, Span: 3, Distribution: "normal"}, expectedError: errGeneratorUnknownDistribution},
+		"with a duplicate rate over 1": {options: Options{Size: 10, Span: 3, Distribution: FixedSpan, DuplicateNameRate: 1.5}, expectedError: errGeneratorInvalidRate},
+		"with negative defects":        {options: Options{Size: 10, Span: 3, Distribution: FixedSpan, Defects: Defects{Orphans: -1}}, expectedError: errGeneratorInvalidDefects},
+		"with too many defects":        {options: Options{Size: 10, Span: 3, Distribution: FixedSpan, Defects: Defects{Orphans: 10}}, expectedError: errGeneratorTooManyDefects},
+		"with nowhere to put a cycle":  {options: Options{Size: 10, Span: 20, Distribution: FixedSpan, Defects: Defects{Cycles: 1}}, expectedError: errGeneratorTooManyDefects},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Generate(tc.options); !errors.Is(err, tc.expectedError) {
+				t.Errorf("The error '%v' was returned, but it was not the expected error '%v'", err, tc.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// Package index saves a parsed chart along with the analyser's lookup tables, so very large charts don't have to be parsed
+// and mapped again every time they're used. An index remembers a checksum of the chart file it was built from, and is only
+// used while the file still matches.
+package index
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/lsg93/org-chart-parser/internal/analysis"
+	"github.com/lsg93/org-chart-parser/internal/model"
+	"github.com/lsg93/org-chart-parser/internal/parser"
+)
+
+// Bumped whenever the layout of an index changes, so indexes from other versions are rebuilt rather than misread.
+const Version uint32 = 1
+
+// Index files sit next to the chart, e.g. avengers.txt.idx.
+const Extension = ".idx"
+
+// Every index file starts with this, then the version and the checksum of the chart, then the chart and tables.
+var magic = [8]byte{'O', 'R', 'G', 'C', 'H', 'I', 'D', 'X'}
+
+var (
+	errIndexNotAnIndex         = errors.New("The file isn't a chart index.")
+	errIndexUnsupportedVersion = errors.New("The index was built by a different version of the tool - run the index command again to rebuild it.")
+	errIndexCorrupt            = errors.New("The index is damaged and can't be read - run the index command again to rebuild it.")
+	errIndexOutOfDate          = errors.New("The chart has changed since the index was built - run the index command again to rebuild it.")
+)
+
+type Index struct {
+	Checksum [sha256.Size]byte // of the chart file the index was built from
+	Chart    model.OrganisationChart
+	Metadata model.ChartMetadata
+	Tables   analysis.Tables
+}
+
+// What's saved after the header - the same as an Index, but with the tables flattened.
+type savedIndex struct {
+	Chart    model.OrganisationChart
+	Metadata model.ChartMetadata
+	Tables   savedTables
+}
+
+type header struct {
+	Magic    [8]byte
+	Version  uint32
+	Checksum [sha256.Size]byte
+}
+
+// Parses the contents of a chart file and builds its tables.
+func Build(source []byte) (Index, error) {
+	chartParser, err := parser.NewOrganisationChartParser(bytes.NewReader(source))
+
+	if err != nil {
+		return Index{}, err
+	}
+
+	chart, err := chartParser.Parse()
+
+	if err != nil {
+		return Index{}, err
+	}
+
+	return Index{Checksum: Checksum(source), Chart: chart, Metadata: chartParser.Metadata(), Tables: analysis.BuildTables(chart)}, nil
+}
+
+func Checksum(source []byte) [sha256.Size]byte {
+	return sha256.Sum256(source)
+}
+
+func (i Index) WriteTo(output io.Writer) (int64, error) {
+	var buffer bytes.Buffer
+
+	if err := binary.Write(&buffer, binary.BigEndian, header{Magic: magic, Version: Version, Checksum: i.Checksum}); err != nil {
+		return 0, err
+	}
+
+	if err := gob.NewEncoder(&buffer).Encode(savedIndex{Chart: i.Chart, Metadata: i.Metadata, Tables: saveTables(i.Tables)}); err != nil {
+		return 0, err
+	}
+
+	return buffer.WriteTo(output)
+}
+
+func Read(input io.Reader) (Index, error) {
+	return read(input, nil)
+}
+
+// Reads the index only if it was built from this chart file - an index for an older version of the file is
+// turned away before the bulk of it is read.
+func ReadFor(input io.Reader, source []byte) (Index, error) {
+	checksum := Checksum(source)
+
+	return read(input, &checksum)
+}
+
+func read(input io.Reader, checksum *[sha256.Size]byte) (Index, error) {
+	var fileHeader header
+
+	if err := binary.Read(input, binary.BigEndian, &fileHeader); err != nil || fileHeader.Magic != magic {
+		return Index{}, errIndexNotAnIndex
+	}
+
+	if fileHeader.Version != Version {
+		return Index{}, fmt.Errorf("%w It's version %d, but this is version %d.", errIndexUnsupportedVersion, fileHeader.Version, Version)
+	}
+
+	if checksum != nil && fileHeader.Checksum != *checksum {
+		return Index{}, errIndexOutOfDate
+	}
+
+	var saved savedIndex
+
+	if err := gob.NewDecoder(input).Decode(&saved); err != nil || !saved.Tables.valid() {
+		return Index{}, errIndexCorrupt
+	}
+
+	return Index{Checksum: fileHeader.Checksum, Chart: saved.Chart, Metadata: saved.Metadata, Tables: saved.Tables.tables()}, nil
+}
@@ -0,0 +1,86 @@
+package index
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const testChart = `---
+name: Avengers Initiative
+as of: 2026-09-01
+---
+| ID | Name           | Manager ID | Dotted Manager IDs | Start Date |
+|----|----------------|------------|--------------------|------------|
+| 1  | Nick Fury      |            |                    |            |
+| 2  | Iron Man       | 1          |                    | 2008-05-02 |
+| 3  | Captain Marvel | 1          | 2                  |            |
+| 6  | Black Widow    | 2          |                    |            |
+| 16 | TBH            | 6          |                    |            |
+`
+
+func writeIndex(t *testing.T, source string) []byte {
+	index, err := Build([]byte(source))
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	var buffer bytes.Buffer
+
+	if _, err := index.WriteTo(&buffer); err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	return buffer.Bytes()
+}
+
+func TestReadingIndexesBack(t *testing.T) {
+	built, _ := Build([]byte(testChart))
+	read, err := ReadFor(bytes.NewReader(writeIndex(t, testChart)), []byte(testChart))
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	if !reflect.DeepEqual(read.Chart, built.Chart) {
+		t.Errorf("The chart %v read back was not equal to the chart %v that was written", read.Chart, built.Chart)
+	}
+
+	if !reflect.DeepEqual(read.Metadata, built.Metadata) {
+		t.Errorf("The metadata %v read back was not equal to the metadata %v that was written", read.Metadata, built.Metadata)
+	}
+
+	if !reflect.DeepEqual(read.Tables, built.Tables) {
+		t.Errorf("The tables %v read back were not equal to the tables %v that were written", read.Tables, built.Tables)
+	}
+}
+
+func TestReadingIndexesErrors(t *testing.T) {
+	written := writeIndex(t, testChart)
+	newerVersion := bytes.Clone(written)
+	newerVersion[11]++
+
+	type testCase struct {
+		index         []byte
+		source        string
+		expectedError error
+	}
+
+	testCases := map[string]testCase{
+		"with a chart that has changed":  {index: written, source: testChart + "| 17 | Hawkeye | 6 | | |\n", expectedError: errIndexOutOfDate},
+		"with a file that isn't indexed": {index: []byte(testChart), source: testChart, expectedError: errIndexNotAnIndex},
+		"with an empty file":             {index: []byte{}, source: testChart, expectedError: errIndexNotAnIndex},
+		"with a different version":       {index: newerVersion, source: testChart, expectedError: errIndexUnsupportedVersion},
+		"with a truncated index":         {index: written[:len(written)/2], source: testChart, expectedError: errIndexCorrupt},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			if _, err := ReadFor(bytes.NewReader(tc.index), []byte(tc.source)); !errors.Is(err, tc.expectedError) {
+				t.Errorf("The error '%v' was returned, but it was not the expected error '%v'", err, tc.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package index
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/lsg93/org-chart-parser/internal/analysis"
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+// The tables as they're saved. Decoding big maps is much slower than decoding slices and building the maps again,
+// so each map is flattened into slices, with its keys sorted so the same chart always gives the same file.
+type savedTables struct {
+	AdjList         intLists
+	Relationships   savedRelationships
+	Names           stringLists
+	NormalisedNames stringLists
+	Reports         intLists
+	Depths          savedDepths
+	Ancestors       intLists
+}
+
+// Every list one after another in Values, with list i running from Offsets[i] to Offsets[i+1].
+type intLists struct {
+	Keys    []int
+	Offsets []int
+	Values  []int
+}
+
+type stringLists struct {
+	Keys    []string
+	Offsets []int
+	Values  []int
+}
+
+type savedRelationships struct {
+	Employees []int
+	Managers  []int
+	Types     []model.RelationshipType
+}
+
+type savedDepths struct {
+	Ids    []int
+	Depths []int
+}
+
+func saveTables(tables analysis.Tables) savedTables {
+	saved := savedTables{
+		AdjList:         saveIntLists(tables.AdjList),
+		Names:           saveStringLists(tables.Names),
+		NormalisedNames: saveStringLists(tables.NormalisedNames),
+		Reports:         saveIntLists(tables.Reports),
+		Ancestors:       saveIntLists(tables.Ancestors),
+	}
+
+	for _, link := range sortedKeys(tables.Relationships, func(a, b [2]int) int { return slices.Compare(a[:], b[:]) }) {
+		saved.Relationships.Employees = append(saved.Relationships.Employees, link[0])
+		saved.Relationships.Managers = append(saved.Relationships.Managers, link[1])
+		saved.Relationships.Types = append(saved.Relationships.Types, tables.Relationships[link])
+	}
+
+	for _, id := range sortedKeys(tables.Depths, cmp.Compare[int]) {
+		saved.Depths.Ids = append(saved.Depths.Ids, id)
+		saved.Depths.Depths = append(saved.Depths.Depths, tables.Depths[id])
+	}
+
+	return saved
+}
+
+// A damaged file can decode into slices that don't line up, which would otherwise only show up as a panic part way through.
+func (saved savedTables) valid() bool {
+	return saved.AdjList.valid() && saved.Names.valid() && saved.NormalisedNames.valid() && saved.Reports.valid() && saved.Ancestors.valid() &&
+		len(saved.Relationships.Managers) == len(saved.Relationships.Employees) && len(saved.Relationships.Types) == len(saved.Relationships.Employees) &&
+		len(saved.Depths.Depths) == len(saved.Depths.Ids)
+}
+
+func (saved savedTables) tables() analysis.Tables {
+	tables := analysis.Tables{
+		AdjList:         saved.AdjList.lists(),
+		Relationships:   make(map[[2]int]model.RelationshipType, len(saved.Relationships.Employees)),
+		Names:           saved.Names.lists(),
+		NormalisedNames: saved.NormalisedNames.lists(),
+		Reports:         saved.Reports.lists(),
+		Depths:          make(map[int]int, len(saved.Depths.Ids)),
+		Ancestors:       saved.Ancestors.lists(),
+	}
+
+	for i, id := range saved.Relationships.Employees {
+		tables.Relationships[[2]int{id, saved.Relationships.Managers[i]}] = saved.Relationships.Types[i]
+	}
+
+	for i, id := range saved.Depths.Ids {
+		tables.Depths[id] = saved.Depths.Depths[i]
+	}
+
+	return tables
+}
+
+func saveIntLists(lists map[int][]int) intLists {
+	saved := intLists{Offsets: []int{0}}
+
+	for _, key := range sortedKeys(lists, cmp.Compare[int]) {
+		saved.Keys = append(saved.Keys, key)
+		saved.Values = append(saved.Values, lists[key]...)
+		saved.Offsets = append(saved.Offsets, len(saved.Values))
+	}
+
+	return saved
+}
+
+func (saved intLists) valid() bool {
+	return validOffsets(saved.Offsets, len(saved.Keys), len(saved.Values))
+}
+
+// Empty lists come back empty rather than nil, the same as when the analyser builds them.
+func (saved intLists) lists() map[int][]int {
+	lists := make(map[int][]int, len(saved.Keys))
+
+	for i, key := range saved.Keys {
+		lists[key] = saved.Values[saved.Offsets[i]:saved.Offsets[i+1]:saved.Offsets[i+1]]
+	}
+
+	return lists
+}
+
+func saveStringLists(lists map[string][]int) stringLists {
+	saved := stringLists{Offsets: []int{0}}
+
+	for _, key := range sortedKeys(lists, cmp.Compare[string]) {
+		saved.Keys = append(saved.Keys, key)
+		saved.Values = append(saved.Values, lists[key]...)
+		saved.Offsets = append(saved.Offsets, len(saved.Values))
+	}
+
+	return saved
+}
+
+func (saved stringLists) valid() bool {
+	return validOffsets(saved.Offsets, len(saved.Keys), len(saved.Values))
+}
+
+func (saved stringLists) lists() map[string][]int {
+	lists := make(map[string][]int, len(saved.Keys))
+
+	for i, key := range saved.Keys {
+		lists[key] = saved.Values[saved.Offsets[i]:saved.Offsets[i+1]:saved.Offsets[i+1]]
+	}
+
+	return lists
+}
+
+func sortedKeys[K comparable, V any](m map[K]V, compare func(a, b K) int) []K {
+	keys := make([]K, 0, len(m))
+
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	slices.SortFunc(keys, compare)
+
+	return keys
+}
+
+func validOffsets(offsets []int, keys int, values int) bool {
+	return len(offsets) == keys+1 && offsets[0] == 0 && offsets[keys] == values && slices.IsSorted(offsets)
+}
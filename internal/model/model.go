@@ -1,9 +1,131 @@
 package model
 
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// The kind of line connecting an employee to a manager on the chart.
+type RelationshipType int
+
+const (
+	SolidLine  RelationshipType = iota // the primary reporting line, held in Employee.ManagerId
+	DottedLine                         // secondary managers, project leads and matrix reporting
+	Interim                            // temporary cover, e.g. while a manager is on leave
+)
+
+var RelationshipTypes = []RelationshipType{SolidLine, DottedLine, Interim}
+
+func (t RelationshipType) String() string {
+	switch t {
+	case SolidLine:
+		return "solid"
+	case DottedLine:
+		return "dotted"
+	case Interim:
+		return "interim"
+	default:
+		return "unknown"
+	}
+}
+
+// Relationship types are written out by name (e.g. in JSON) rather than as numbers.
+func (t RelationshipType) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *RelationshipType) UnmarshalText(text []byte) error {
+	for _, relationshipType := range RelationshipTypes {
+		if relationshipType.String() == string(text) {
+			*t = relationshipType
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown relationship type '%s'", text)
+}
+
+// A secondary reporting line to another manager.
+type Relationship struct {
+	ManagerId int              `json:"managerId"`
+	Type      RelationshipType `json:"type"`
+}
+
+// The dates a row applies between, for charts with effective-dated rows.
+// Both ends are inclusive, and a zero date leaves that end open.
+type DateRange struct {
+	From time.Time `json:"from,omitzero"`
+	To   time.Time `json:"to,omitzero"`
+}
+
+func (r DateRange) Contains(date time.Time) bool {
+	return (r.From.IsZero() || !date.Before(r.From)) && (r.To.IsZero() || !date.After(r.To))
+}
+
+// Each row in the chart is really a position, and usually there's someone in it.
+// A vacant position (an open role) has no one in it, but still has a manager and can still have reports - its Name is just a label, e.g. "TBH - Senior Engineer".
 type Employee struct {
-	Id        int
-	Name      string
-	ManagerId int
+	Id            int            `json:"id"`
+	Name          string         `json:"name"`
+	ManagerId     int            `json:"managerId,omitempty"`
+	Title         string         `json:"title,omitempty"`         // optional - only populated when the input has a Title column
+	Department    string         `json:"department,omitempty"`    // optional - only populated when the input has a Department column
+	Relationships []Relationship `json:"relationships,omitempty"` // secondary managers only - the solid line manager stays in ManagerId
+	Effective     DateRange      `json:"effective,omitzero"`      // optional - only populated when the input has Start Date or End Date columns
+	Vacant        bool           `json:"vacant,omitempty"`        // from a Vacant column, or a name like "TBH" when vacancies are guessed (see GuessVacancies)
+}
+
+// Every manager this employee reports to, solid line first.
+func (e Employee) Managers() []Relationship {
+	managers := make([]Relationship, 0, len(e.Relationships)+1)
+
+	if e.ManagerId != 0 {
+		managers = append(managers, Relationship{ManagerId: e.ManagerId, Type: SolidLine})
+	}
+
+	return append(managers, e.Relationships...)
+}
+
+// Names that are usually labels for an open role rather than someone's name.
+var vacancyNamePrefixes = []string{"tbh", "tbd", "vacant", "vacancy"}
+
+// Whether a name is one of the usual labels for an open role, e.g. "TBH", "TBD - Senior Engineer" or "Vacancy (Head of Ops)".
+// The label has to be a whole word, so "Tbdavies" isn't a vacancy.
+func IsVacancyName(name string) bool {
+	lower := strings.ToLower(strings.TrimSpace(name))
+
+	for _, prefix := range vacancyNamePrefixes {
+		rest, ok := strings.CutPrefix(lower, prefix)
+
+		if ok && (rest == "" || !unicode.IsLetter([]rune(rest)[0])) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Marks every position named like an open role as vacant, for charts that label vacancies instead of having a Vacant column.
+// Real names can look like labels too ("Tbd Smith", "Vacant Jones"), so this is only ever done when asked for.
+func GuessVacancies(chart OrganisationChart) OrganisationChart {
+	guessed := make(OrganisationChart, len(chart))
+
+	for i, employee := range chart {
+		employee.Vacant = employee.Vacant || IsVacancyName(employee.Name)
+		guessed[i] = employee
+	}
+
+	return guessed
 }
 
 type OrganisationChart = []Employee
+
+// Details about the chart itself, from the front matter at the top of the file. Everything is optional.
+type ChartMetadata struct {
+	Name   string    `json:"name,omitempty"`
+	AsOf   time.Time `json:"asOf,omitzero"`    // the date the chart was accurate on - zero if not given
+	Source string    `json:"source,omitempty"` // the system the chart was exported from
+	Format string    `json:"format,omitempty"` // the output format diff uses unless told otherwise
+}
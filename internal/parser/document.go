@@ -0,0 +1,362 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+var (
+	errDocumentDuplicateId   = errors.New("The chart has more than one row for the same employee, so it can't be edited in place.")
+	errDocumentMissingColumn = errors.New("The chart doesn't have a column for one of the values being saved - add the column to the header first.")
+)
+
+// A chart file held line by line, so changes can be saved without disturbing anything else in it -
+// front matter, comments, the rest of a Markdown document, the order of the rows and the widths of the columns all stay as they were.
+type ChartDocument struct {
+	lines      []string
+	lineEnding string
+	newlineEnd bool // whether the last line had a line ending
+	parser     *orgChartFileParser
+	chart      model.OrganisationChart
+}
+
+func ReadChartDocument(input io.Reader) (*ChartDocument, error) {
+	content, err := io.ReadAll(input)
+
+	if err != nil {
+		return nil, errParserScanError
+	}
+
+	parser := &orgChartFileParser{input: bytes.NewReader(content)}
+	chart, err := parser.Parse()
+
+	if err != nil {
+		return nil, err
+	}
+
+	text := string(content)
+	document := &ChartDocument{lineEnding: "\n", parser: parser, chart: chart}
+
+	if strings.Contains(text, "\r\n") {
+		document.lineEnding = "\r\n"
+	}
+
+	document.newlineEnd = strings.HasSuffix(text, "\n")
+	document.lines = strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+
+	for i, line := range document.lines {
+		document.lines[i] = strings.TrimSuffix(line, "\r")
+	}
+
+	return document, nil
+}
+
+func (d *ChartDocument) Chart() model.OrganisationChart {
+	return slices.Clone(d.chart)
+}
+
+func (d *ChartDocument) Metadata() model.ChartMetadata {
+	return d.parser.Metadata()
+}
+
+// Brings the table in line with the chart, matching rows up by ID. Rows for employees that aren't in the chart any more are dropped,
+// rows that have changed only have the cells that changed rewritten, and new employees go on the end of the table.
+func (d *ChartDocument) Apply(chart model.OrganisationChart) error {
+	current, err := indexById(d.chart)
+
+	if err != nil {
+		return err
+	}
+
+	updated, err := indexById(chart)
+
+	if err != nil {
+		return err
+	}
+
+	for _, employee := range chart {
+		if err := d.checkColumns(employee); err != nil {
+			return err
+		}
+	}
+
+	table := d.table()
+	removed := make(map[int]bool)
+
+	for i, employee := range d.chart {
+		line := d.parser.rowLines[i]
+		j, ok := updated[employee.Id]
+
+		if !ok {
+			removed[line] = true
+			continue
+		}
+
+		for column, cell := range d.parser.columns {
+			before, after := columnValues[column](employee), columnValues[column](chart[j])
+
+			if before != after {
+				table.setCell(line, cell, after)
+			}
+		}
+	}
+
+	added := make([]int, 0)
+
+	for _, employee := range chart {
+		if _, ok := current[employee.Id]; ok {
+			continue
+		}
+
+		added = append(added, table.addRow(d.rowTemplate()))
+
+		for column, cell := range d.parser.columns {
+			table.setCell(added[len(added)-1], cell, columnValues[column](employee))
+		}
+	}
+
+	// The new rows go straight after the last line of the table, in the order they're in the chart.
+	end := d.parser.tableLines[len(d.parser.tableLines)-1]
+	lines := make([]string, 0, len(d.lines)+len(added))
+
+	for i, line := range d.lines {
+		if row, ok := table.rows[i]; ok && !removed[i] {
+			lines = append(lines, row.String())
+		} else if !removed[i] {
+			lines = append(lines, line)
+		}
+
+		// The last row may have just been removed, but the new rows still go where it was.
+		if i == end {
+			for _, row := range added {
+				lines = append(lines, table.rows[row].String())
+			}
+		}
+	}
+
+	document, err := ReadChartDocument(strings.NewReader(d.joinLines(lines)))
+
+	if err != nil {
+		return err
+	}
+
+	*d = *document
+
+	return nil
+}
+
+func (d *ChartDocument) String() string {
+	return d.joinLines(d.lines)
+}
+
+func (d *ChartDocument) WriteTo(output io.Writer) (int64, error) {
+	n, err := io.WriteString(output, d.String())
+
+	return int64(n), err
+}
+
+func (d *ChartDocument) joinLines(lines []string) string {
+	text := strings.Join(lines, d.lineEnding)
+
+	if d.newlineEnd {
+		text += d.lineEnding
+	}
+
+	return text
+}
+
+// Values can only be saved if the table has somewhere to put them.
+func (d *ChartDocument) checkColumns(employee model.Employee) error {
+	for column, value := range columnValues {
+		if _, ok := d.parser.columns[column]; !ok && value(employee) != "" {
+			return fmt.Errorf("%w %s (%d) has a %s.", errDocumentMissingColumn, employee.Name, employee.Id, column)
+		}
+	}
+
+	return nil
+}
+
+// New rows are laid out like the last row in the table, or the header if there aren't any rows yet.
+func (d *ChartDocument) rowTemplate() string {
+	if len(d.parser.rowLines) > 0 {
+		return d.lines[d.parser.rowLines[len(d.parser.rowLines)-1]]
+	}
+
+	return d.lines[d.parser.tableLines[0]]
+}
+
+func (d *ChartDocument) table() *editableTable {
+	table := &editableTable{rows: make(map[int]*tableRow), separator: d.parser.separatorLine, next: len(d.lines)}
+
+	for _, line := range d.parser.tableLines {
+		table.rows[line] = splitTableRow(d.lines[line])
+	}
+
+	table.widths = make([]int, d.parser.width)
+
+	// A column is aligned when every cell in it is the same width - the separator doesn't count, as it's often just |---|.
+	for cell := range table.widths {
+		widths := make(map[int]bool)
+
+		for line, row := range table.rows {
+			if line != table.separator {
+				widths[len([]rune(row.cells[cell]))] = true
+			}
+		}
+
+		if len(widths) == 1 {
+			for width := range widths {
+				table.widths[cell] = width
+			}
+		}
+	}
+
+	return table
+}
+
+func indexById(chart model.OrganisationChart) (map[int]int, error) {
+	index := make(map[int]int, len(chart))
+
+	for i, employee := range chart {
+		if _, ok := index[employee.Id]; ok {
+			return nil, fmt.Errorf("%w ID %d is used more than once.", errDocumentDuplicateId, employee.Id)
+		}
+
+		index[employee.Id] = i
+	}
+
+	return index, nil
+}
+
+// The lines of the table, by line number. New rows are numbered from the end of the document so they don't clash.
+type editableTable struct {
+	rows      map[int]*tableRow
+	widths    []int // the width of each aligned column, or 0 when the column isn't aligned
+	separator int
+	next      int
+}
+
+func (t *editableTable) addRow(template string) int {
+	t.rows[t.next] = splitTableRow(template)
+	t.next++
+
+	return t.next - 1
+}
+
+// Writes a value into a cell. Aligned columns are padded to their width, and widened everywhere if the value doesn't fit.
+func (t *editableTable) setCell(line int, cell int, value string) {
+	value = strings.ReplaceAll(value, "|", `\|`)
+	width := len([]rune(value)) + 2
+
+	// Cells in a column that isn't aligned keep whatever spacing they had, unless they were empty.
+	if t.widths[cell] == 0 {
+		current := t.rows[line].cells[cell]
+		trimmed := strings.TrimSpace(current)
+
+		if trimmed == "" {
+			t.rows[line].cells[cell] = " " + value + " "
+			return
+		}
+
+		i := strings.Index(current, trimmed)
+		t.rows[line].cells[cell] = current[:i] + value + current[i+len(trimmed):]
+		return
+	}
+
+	if width > t.widths[cell] {
+		for i, row := range t.rows {
+			if i == t.separator {
+				row.cells[cell] = widenSeparator(row.cells[cell], width)
+			} else {
+				row.cells[cell] = padCell(strings.TrimRightFunc(row.cells[cell], unicode.IsSpace), width)
+			}
+		}
+
+		t.widths[cell] = width
+	}
+
+	t.rows[line].cells[cell] = padCell(" "+value, t.widths[cell])
+}
+
+func padCell(cell string, width int) string {
+	return cell + strings.Repeat(" ", max(0, width-len([]rune(cell))))
+}
+
+// Adds dashes after the last one, so any alignment colon stays on the end.
+func widenSeparator(cell string, width int) string {
+	extra := width - len([]rune(cell))
+
+	if extra <= 0 {
+		return cell
+	}
+
+	i := strings.LastIndex(cell, "-") + 1
+
+	return cell[:i] + strings.Repeat("-", extra) + cell[i:]
+}
+
+// A line of the table split into cells without trimming anything, so it can be put back together exactly as it was.
+type tableRow struct {
+	indent, trailing string
+	leadingPipe      bool
+	trailingPipe     bool
+	cells            []string // still escaped
+}
+
+func splitTableRow(line string) *tableRow {
+	trimmed := strings.TrimLeftFunc(line, unicode.IsSpace)
+	row := &tableRow{indent: line[:len(line)-len(trimmed)]}
+	content := strings.TrimRightFunc(trimmed, unicode.IsSpace)
+	row.trailing = trimmed[len(content):]
+
+	start := 0
+
+	for i := 0; i < len(content); i++ {
+		switch {
+		case content[i] == '\\' && i+1 < len(content) && content[i+1] == '|':
+			i++
+		case content[i] == '|':
+			row.cells = append(row.cells, content[start:i])
+			start = i + 1
+		}
+	}
+
+	row.cells = append(row.cells, content[start:])
+
+	// The same rules as normaliseLineSlice for which pipes are just the edges of the table.
+	if len(row.cells) > 1 && strings.HasPrefix(content, "|") {
+		row.leadingPipe, row.cells = true, row.cells[1:]
+	}
+
+	if len(row.cells) > 1 && row.cells[len(row.cells)-1] == "" {
+		row.trailingPipe, row.cells = true, row.cells[:len(row.cells)-1]
+	}
+
+	return row
+}
+
+func (row *tableRow) String() string {
+	var builder strings.Builder
+	builder.WriteString(row.indent)
+
+	if row.leadingPipe {
+		builder.WriteString("|")
+	}
+
+	builder.WriteString(strings.Join(row.cells, "|"))
+
+	if row.trailingPipe {
+		builder.WriteString("|")
+	}
+
+	builder.WriteString(row.trailing)
+
+	return builder.String()
+}
@@ -0,0 +1,151 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+func setupDocument(input string, t *testing.T) *ChartDocument {
+	document, err := ReadChartDocument(strings.NewReader(input))
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	return document
+}
+
+func TestApplyingChangesToChartDocuments(t *testing.T) {
+	type testCase struct {
+		input          string
+		edit           func(chart model.OrganisationChart) model.OrganisationChart
+		expectedOutput string
+	}
+
+	testCases := map[string]testCase{
+		"without any changes": {
+			input:          "---\r\nname: Avengers\r\n---\r\n| ID | Name | Manager ID |\r\n| 1 | Nick Fury | |\r\n# Iron Man's team\r\n|2|Iron Man|1|",
+			edit:           func(chart model.OrganisationChart) model.OrganisationChart { return chart },
+			expectedOutput: "---\r\nname: Avengers\r\n---\r\n| ID | Name | Manager ID |\r\n| 1 | Nick Fury | |\r\n# Iron Man's team\r\n|2|Iron Man|1|",
+		},
+		"in an aligned table": {
+			input: "The Avengers, as of September.\n" +
+				"\n" +
+				"| ID | Name      | Manager ID |\n" +
+				"|----|-----------|-----------:|\n" +
+				"| 1  | Nick Fury |            |\n" +
+				"# Iron Man's team\n" +
+				"| 2  | Iron Man  | 1          |\n" +
+				"| 6  | Hawkeye   | 2          |\n" +
+				"\n" +
+				"Last updated in September.\n",
+			edit: func(chart model.OrganisationChart) model.OrganisationChart {
+				chart[2].ManagerId = 1
+				chart[0].Name = "Director Nick Fury"
+				return append(chart[:1], chart[2], model.Employee{Id: 7, Name: "Maria Hill", ManagerId: 1})
+			},
+			expectedOutput: "The Avengers, as of September.\n" +
+				"\n" +
+				"| ID | Name               | Manager ID |\n" +
+				"|----|--------------------|-----------:|\n" +
+				"| 1  | Director Nick Fury |            |\n" +
+				"# Iron Man's team\n" +
+				"| 6  | Hawkeye            | 1          |\n" +
+				"| 7  | Maria Hill         | 1          |\n" +
+				"\n" +
+				"Last updated in September.\n",
+		},
+		"removing the last row while adding one": {
+			input: "The Avengers, as of September.\n" +
+				"\n" +
+				"| ID | Name      | Manager ID |\n" +
+				"| 1  | Nick Fury |            |\n" +
+				"| 2  | Iron Man  | 1          |\n" +
+				"\n" +
+				"Last updated in September.\n",
+			edit: func(chart model.OrganisationChart) model.OrganisationChart {
+				return append(chart[:1], model.Employee{Id: 3, Name: "Hawkeye", ManagerId: 1})
+			},
+			expectedOutput: "The Avengers, as of September.\n" +
+				"\n" +
+				"| ID | Name      | Manager ID |\n" +
+				"| 1  | Nick Fury |            |\n" +
+				"| 3  | Hawkeye   | 1          |\n" +
+				"\n" +
+				"Last updated in September.\n",
+		},
+		"in a table that isn't aligned": {
+			input: "ID | Name | Manager ID | Dotted Manager IDs\n" +
+				"1 | Nick Fury | | 2\n" +
+				"2 | Iron Man | 1 | 3;6\n" +
+				"3 | Captain Marvel | 1 | 2\n",
+			edit: func(chart model.OrganisationChart) model.OrganisationChart {
+				chart[1].ManagerId = 3
+				chart[2].Name = "Carol | Danvers"
+				return append(chart, model.Employee{Id: 4, Name: "Hulk", ManagerId: 3, Relationships: []model.Relationship{{ManagerId: 2, Type: model.DottedLine}}})
+			},
+			expectedOutput: "ID | Name | Manager ID | Dotted Manager IDs\n" +
+				"1 | Nick Fury | | 2\n" +
+				"2 | Iron Man | 3 | 3;6\n" +
+				"3 | Carol \\| Danvers | 1 | 2\n" +
+				"4 | Hulk | 3 | 2\n",
+		},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			document := setupDocument(tc.input, t)
+
+			if err := document.Apply(tc.edit(document.Chart())); err != nil {
+				t.Fatalf("An error '%s' was returned when none was expected", err)
+			}
+
+			if document.String() != tc.expectedOutput {
+				t.Errorf("The received output '%s' was not equal to the expected output '%s'", document.String(), tc.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestFailsToApplyChangesTheDocumentCantHold(t *testing.T) {
+	type testCase struct {
+		input         string
+		chart         model.OrganisationChart
+		expectedError error
+	}
+
+	testCases := map[string]testCase{
+		"with a value for a column that isn't in the table": {
+			input:         "| ID | Name | Manager ID |\n| 1 | Nick Fury | |",
+			chart:         model.OrganisationChart{{Id: 1, Name: "Nick Fury", Title: "Director"}},
+			expectedError: errDocumentMissingColumn,
+		},
+		"with an ID used twice in the chart": {
+			input:         "| ID | Name | Manager ID |\n| 1 | Nick Fury | |",
+			chart:         model.OrganisationChart{{Id: 1, Name: "Nick Fury"}, {Id: 1, Name: "Maria Hill"}},
+			expectedError: errDocumentDuplicateId,
+		},
+		"with an ID used twice in the document": {
+			input:         "| ID | Name | Manager ID | End Date |\n| 1 | Nick Fury | | 2026-01-01 |\n| 1 | Maria Hill | | |",
+			chart:         model.OrganisationChart{{Id: 1, Name: "Maria Hill"}},
+			expectedError: errDocumentDuplicateId,
+		},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			document := setupDocument(tc.input, t)
+
+			if err := document.Apply(tc.chart); !errors.Is(err, tc.expectedError) {
+				t.Errorf("The error '%v' was returned, but it was not the expected error '%v'", err, tc.expectedError)
+			}
+
+			if document.String() != tc.input {
+				t.Errorf("The document was changed to '%s' even though the changes couldn't be applied.", document.String())
+			}
+		})
+	}
+}
@@ -2,11 +2,14 @@ package parser
 
 import (
 	"bufio"
+	"context"
 	"errors"
+	"fmt"
 	"io"
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/lsg93/org-chart-parser/internal/model"
 )
@@ -16,18 +19,72 @@ import (
 // In a real project, to aid OCP it would probably be better to split the parsing logic out from this contract into a separate file - e.g. file_parser.go
 type OrganisationChartParser interface {
 	Parse() (model.OrganisationChart, error)
+	Metadata() model.ChartMetadata // only populated once Parse has been called
+	// The same as Parse, but stops early with the context's error once the context is cancelled.
+	ParseContext(ctx context.Context) (model.OrganisationChart, error)
 }
 
 var (
-	errParserScanError         = errors.New("The input data could not be scanned line by line.")
-	errParserEmptyInput        = errors.New("Provided input to the parser was empty.")
-	errParserInvalidHeader     = errors.New("No header with appropriate column names was found in given input.")
-	errParserInvalidIdField    = errors.New("A problem was encountered when parsing the ID field - Check that your input has correct ID fields.")
-	errParserInvalidLineLength = errors.New("One of the lines in the input has too many, or too few fields.")
+	errParserScanError          = errors.New("The input data could not be scanned line by line.")
+	errParserEmptyInput         = errors.New("Provided input to the parser was empty.")
+	errParserInvalidHeader      = errors.New("No header with appropriate column names was found in given input.")
+	errParserInvalidIdField     = errors.New("A problem was encountered when parsing the ID field - Check that your input has correct ID fields.")
+	errParserInvalidLineLength  = errors.New("One of the lines in the input has too many, or too few fields.")
+	errParserInvalidDateField   = errors.New("A problem was encountered when parsing a date field - dates should look like 2026-09-01, and a start date can't be after its end date.")
+	errParserInvalidVacantField = errors.New("A problem was encountered when parsing the Vacant field - it should be yes, no or blank.")
+	errParserInvalidMetadata    = errors.New("The front matter at the top of the input is not valid - it should be 'key: value' lines between two '---' lines.")
+	errParserCancelled          = errors.New("Parsing was stopped before the whole input was read.")
 )
 
+// Keys that can be set in the front matter, e.g.
+//
+//	---
+//	name: Avengers Initiative
+//	as of: 2026-09-01
+//	source: HR export
+//	format: json
+//	---
+const (
+	metadataName   = "name"
+	metadataAsOf   = "as of"
+	metadataSource = "source"
+	metadataFormat = "format"
+)
+
+// Column names as they appear in the header (lowercased).
+// ID, name and manager ID are required - anything else is optional and can appear in any order.
+const (
+	columnId         = "id"
+	columnName       = "name"
+	columnManagerId  = "manager id"
+	columnTitle      = "title"
+	columnDepartment = "department"
+	columnDotted     = "dotted manager ids"
+	columnInterim    = "interim manager ids"
+	columnStartDate  = "start date"
+	columnEndDate    = "end date"
+	columnVacant     = "vacant"
+)
+
+var requiredColumns = []string{columnId, columnName, columnManagerId}
+var optionalColumns = []string{columnTitle, columnDepartment, columnDotted, columnInterim, columnStartDate, columnEndDate, columnVacant}
+
+// Secondary relationship columns hold a list of manager IDs, separated by commas or spaces.
+var relationshipColumns = map[string]model.RelationshipType{
+	columnDotted:  model.DottedLine,
+	columnInterim: model.Interim,
+}
+
 type orgChartFileParser struct {
-	input io.Reader
+	input    io.Reader
+	columns  map[string]int // column name > index, worked out from the header
+	width    int            // number of columns every row should have
+	metadata model.ChartMetadata
+
+	// Where the table is in the input (counting lines from 0), so a ChartDocument can edit it in place.
+	tableLines    []int // the header, separator and every row, including empty ones
+	separatorLine int   // -1 when there isn't one
+	rowLines      []int // the line each employee in the chart came from
 }
 
 func NewOrganisationChartParser(input io.Reader) (OrganisationChartParser, error) {
@@ -36,36 +93,103 @@ func NewOrganisationChartParser(input io.Reader) (OrganisationChartParser, error
 }
 
 func (parser *orgChartFileParser) Parse() (model.OrganisationChart, error) {
+	return parser.ParseContext(context.Background())
+}
+
+// Cancellation is checked on every line, which costs next to nothing next to splitting the line up.
+func (parser *orgChartFileParser) ParseContext(ctx context.Context) (model.OrganisationChart, error) {
 	chart := model.OrganisationChart{}
+	done := ctx.Done()
 
 	scanner := bufio.NewScanner(parser.input)
 
-	i := 0
+	// The table doesn't have to be the whole input - it can be the first table in a larger Markdown document.
+	// In that case Markdown's rules apply, and the table ends at the first line that isn't part of it.
+	nonEmpty, headerFound, inDocument, afterHeader, inMetadata := false, false, false, false, false
+	lineNumber := -1
+
 	for scanner.Scan() {
+		select {
+		case <-done:
+			return chart, errors.Join(errParserCancelled, ctx.Err())
+		default:
+		}
+
+		lineNumber++
 		line := strings.TrimSpace(scanner.Text())
 
-		// Skip empty lines - this accommodates leading whitespace.
 		if len(line) == 0 {
+			// A blank line ends a table in Markdown, but a plain table file can be spaced out however it likes.
+			if headerFound && inDocument {
+				break
+			}
+
 			continue
 		}
 
-		if i == 0 {
-			// Split header and check it has correct column names
-			// If not, return error, as input is malformed.
+		// Comments can go anywhere, and are ignored entirely. Once the table has started though, a line with a pipe in it is a row,
+		// even if its first cell starts with a # (e.g. "#1 Fan | 4 | 2" in a table without outer pipes).
+		if strings.HasPrefix(line, "#") && !(headerFound && strings.Contains(line, "|")) {
+			continue
+		}
+
+		// Front matter has to come before anything else.
+		if !nonEmpty && line == "---" {
+			nonEmpty, inMetadata = true, true
+			continue
+		}
+
+		nonEmpty = true
+
+		if inMetadata {
+			if line == "---" {
+				inMetadata = false
+			} else if err := parser.setMetadata(line); err != nil {
+				return chart, err
+			}
+
+			continue
+		}
+
+		if !headerFound {
+			// Split header and check it has correct column names.
+			// Anything before it is taken to be the rest of the document.
 			if !parser.validateHeader(line) {
-				return chart, errParserInvalidHeader
+				inDocument = true
+				continue
 			}
-			i++
+
+			headerFound, afterHeader = true, true
+			parser.tableLines, parser.separatorLine = []int{lineNumber}, -1
 			continue
 		}
 
+		cells := normaliseLineSlice(line)
+
+		// Markdown puts a separator row (|---|:---:|) between the header and the rows.
+		if afterHeader {
+			afterHeader = false
+
+			if len(cells) == parser.width && isSeparatorRow(cells) {
+				parser.tableLines, parser.separatorLine = append(parser.tableLines, lineNumber), lineNumber
+				continue
+			}
+		}
+
+		// Prose straight after a table in a document - a row needs at least one pipe.
+		if inDocument && len(cells) == 1 {
+			break
+		}
+
 		// Stopping on failure is better for something without a UI I think.
-		validated, err := parser.validateLine(line)
+		validated, err := parser.validateLine(cells)
 
 		if err != nil {
 			return chart, err
 		}
 
+		parser.tableLines = append(parser.tableLines, lineNumber)
+
 		if validated == nil {
 			// Empty row - continue on.
 			continue
@@ -74,84 +198,212 @@ func (parser *orgChartFileParser) Parse() (model.OrganisationChart, error) {
 		// marshal line into struct.
 		employee := parser.marshalLine(validated)
 		chart = append(chart, employee)
+		parser.rowLines = append(parser.rowLines, lineNumber)
 	}
 
-	// If no scanner iterations took place, then the input was likely empty.
-	if i == 0 {
+	if err := scanner.Err(); err != nil {
+		return chart, errParserScanError
+	}
+
+	if !nonEmpty {
 		return chart, errParserEmptyInput
 	}
 
-	if err := scanner.Err(); err != nil {
-		return chart, errParserScanError
+	if inMetadata {
+		return chart, fmt.Errorf("%w The closing '---' is missing.", errParserInvalidMetadata)
+	}
+
+	if !headerFound {
+		return chart, errParserInvalidHeader
 	}
 
 	return chart, nil
 }
 
-func (parser *orgChartFileParser) validateHeader(headerLine string) bool {
-	headerNames := []string{"id", "name", "manager id"}
-	colNames := normaliseLineSlice(strings.Split(headerLine, "|"))
+func (parser *orgChartFileParser) Metadata() model.ChartMetadata {
+	return parser.metadata
+}
+
+// Reads a single 'key: value' line of front matter. Keys are case insensitive, and "as-of" or "as_of" work as well as "as of".
+// Keys the parser doesn't use are ignored, so front matter written for other tools doesn't stop the chart being read.
+func (parser *orgChartFileParser) setMetadata(line string) error {
+	key, value, ok := strings.Cut(line, ":")
+	key = strings.NewReplacer("-", " ", "_", " ").Replace(strings.ToLower(strings.TrimSpace(key)))
+	value = strings.TrimSpace(value)
+
+	if !ok {
+		return fmt.Errorf("%w '%s' is not a 'key: value' line.", errParserInvalidMetadata, line)
+	}
+
+	switch key {
+	case metadataName:
+		parser.metadata.Name = value
+	case metadataAsOf:
+		asOf, err := time.Parse(time.DateOnly, value)
+
+		if err != nil {
+			return fmt.Errorf("%w The as of date '%s' should look like 2026-09-01.", errParserInvalidMetadata, value)
+		}
 
-	if len(colNames) != 3 {
-		return false
+		parser.metadata.AsOf = asOf
+	case metadataSource:
+		parser.metadata.Source = value
+	case metadataFormat:
+		parser.metadata.Format = strings.ToLower(value)
 	}
 
-	return slices.Equal(headerNames, lowercaseSlice(colNames))
+	// Other keys (a title, an owner...) are left for whoever else reads the file.
+
+	return nil
 }
 
-func (parser *orgChartFileParser) validateLine(line string) ([]string, error) {
+func (parser *orgChartFileParser) validateHeader(headerLine string) bool {
+	colNames := lowercaseSlice(normaliseLineSlice(headerLine))
+	columns := make(map[string]int)
 
-	s := normaliseLineSlice(strings.Split(line, "|"))
+	for i, colName := range colNames {
+		known := slices.Contains(requiredColumns, colName) || slices.Contains(optionalColumns, colName)
 
-	if len(s) != 3 {
-		return nil, errParserInvalidLineLength
+		// Unknown or repeated columns mean this isn't a header we can work with.
+		if _, seen := columns[colName]; seen || !known {
+			return false
+		}
+
+		columns[colName] = i
 	}
 
-	employeeId := s[0]
-	name := s[1]
-	managerId := s[2]
+	for _, colName := range requiredColumns {
+		if _, ok := columns[colName]; !ok {
+			return false
+		}
+	}
+
+	parser.columns = columns
+	parser.width = len(colNames)
+
+	return true
+}
+
+func (parser *orgChartFileParser) validateLine(s []string) ([]string, error) {
+	if len(s) != parser.width {
+		return nil, errParserInvalidLineLength
+	}
 
 	// Edge case for empty rows
-	if employeeId == "" && name == "" && managerId == "" {
+	if !slices.ContainsFunc(s, func(v string) bool { return v != "" }) {
 		return nil, nil
 	}
 
-	if employeeId == managerId || employeeId == "" {
-		return nil, errParserInvalidIdField
-	}
+	employeeId, err := strconv.Atoi(parser.field(s, columnId))
 
 	// Check employee ID is numeric.
-	if _, err := strconv.Atoi(s[0]); err != nil {
+	if err != nil {
 		return nil, errParserInvalidIdField
 	}
 
-	// Check manager ID is numeric.
-	if _, err := strconv.Atoi(s[2]); err != nil {
-		// Only error if the error occurs when the manager ID is not blank.
-		if s[2] != "" {
+	// Check manager ID is numeric - only error if it isn't blank.
+	// IDs are compared as numbers, so "01" managing "1" is still caught.
+	if managerId := parser.field(s, columnManagerId); managerId != "" {
+		if id, err := strconv.Atoi(managerId); err != nil || id == employeeId {
 			return nil, errParserInvalidIdField
 		}
 	}
 
+	for column := range relationshipColumns {
+		for _, managerId := range splitIdList(parser.field(s, column)) {
+			if id, err := strconv.Atoi(managerId); err != nil || id == employeeId {
+				return nil, errParserInvalidIdField
+			}
+		}
+	}
+
+	// Effective dates are optional, but have to be real dates the right way round when they're given.
+	effective, err := parser.effectiveDates(s)
+
+	if err != nil || (!effective.From.IsZero() && !effective.To.IsZero() && effective.From.After(effective.To)) {
+		return nil, errParserInvalidDateField
+	}
+
+	if _, ok := parseVacant(parser.field(s, columnVacant)); !ok {
+		return nil, errParserInvalidVacantField
+	}
+
 	return s, nil
 }
 
+// Yes/no values for the Vacant column - blank means the position is filled, unless its name says otherwise.
+func parseVacant(value string) (bool, bool) {
+	switch strings.ToLower(value) {
+	case "yes", "y", "true", "x":
+		return true, true
+	case "", "no", "n", "false":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+func (parser *orgChartFileParser) effectiveDates(s []string) (model.DateRange, error) {
+	var effective model.DateRange
+	var err error
+
+	if from := parser.field(s, columnStartDate); from != "" {
+		if effective.From, err = time.Parse(time.DateOnly, from); err != nil {
+			return effective, err
+		}
+	}
+
+	if to := parser.field(s, columnEndDate); to != "" {
+		if effective.To, err = time.Parse(time.DateOnly, to); err != nil {
+			return effective, err
+		}
+	}
+
+	return effective, nil
+}
+
 func (parser *orgChartFileParser) marshalLine(s []string) model.Employee {
 	// Fairly confident the errors can be ignored, as input should have been validated @ this point.
 	// This could be better though I think.
-	employeeId, _ := strconv.Atoi(s[0])
-	name := s[1]
-	managerId, _ := strconv.Atoi(s[2])
+	employeeId, _ := strconv.Atoi(parser.field(s, columnId))
+	name := parser.field(s, columnName)
+	managerId, _ := strconv.Atoi(parser.field(s, columnManagerId))
 
 	employee := model.Employee{
-		Id:        employeeId,
-		Name:      name,
-		ManagerId: managerId,
+		Id:         employeeId,
+		Name:       name,
+		ManagerId:  managerId,
+		Title:      parser.field(s, columnTitle),
+		Department: parser.field(s, columnDepartment),
+	}
+
+	// Already validated, so the errors can be ignored.
+	employee.Effective, _ = parser.effectiveDates(s)
+	vacant, _ := parseVacant(parser.field(s, columnVacant))
+	employee.Vacant = vacant
+
+	// Iterate in a fixed order so relationships always come out the same way round.
+	for _, column := range []string{columnDotted, columnInterim} {
+		for _, id := range splitIdList(parser.field(s, column)) {
+			managerId, _ := strconv.Atoi(id)
+			employee.Relationships = append(employee.Relationships, model.Relationship{ManagerId: managerId, Type: relationshipColumns[column]})
+		}
 	}
 
 	return employee
 }
 
+// Looks up a value in a row by column name - optional columns that aren't in the header are blank.
+func (parser *orgChartFileParser) field(s []string, column string) string {
+	i, ok := parser.columns[column]
+
+	if !ok {
+		return ""
+	}
+
+	return s[i]
+}
+
 // Might need to move these helpers later down the line.
 
 func lowercaseSlice(s []string) []string {
@@ -164,12 +416,60 @@ func lowercaseSlice(s []string) []string {
 	return ls
 }
 
-func normaliseLineSlice(s []string) []string {
+// Splits a row into trimmed fields, following Markdown's rules - the leading and trailing pipes are optional,
+// and an escaped pipe (\|) is part of the field rather than the end of it.
+func normaliseLineSlice(line string) []string {
+	cells := []string{}
+	var cell strings.Builder
+
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, cell.String())
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+
+	cells = append(cells, cell.String())
+
+	// An empty first or last field can only come from a leading or trailing pipe, which just marks the edge of the table.
+	if len(cells) > 1 && strings.HasPrefix(line, "|") {
+		cells = cells[1:]
+	}
+
+	if len(cells) > 1 && cells[len(cells)-1] == "" {
+		cells = cells[:len(cells)-1]
+	}
+
 	ts := []string{}
 
-	for _, v := range s[1 : len(s)-1] {
+	for _, v := range cells {
 		ts = append(ts, strings.TrimSpace(v))
 	}
 
 	return ts
 }
+
+// Separator rows are made of dashes, with an optional colon at either end for alignment - e.g. |---|:---:|---:|
+func isSeparatorRow(cells []string) bool {
+	for _, cell := range cells {
+		trimmed := strings.TrimSuffix(strings.TrimPrefix(cell, ":"), ":")
+
+		if trimmed == "" || strings.Trim(trimmed, "-") != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+func splitIdList(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ';' || r == ' '
+	})
+}
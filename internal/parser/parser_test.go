@@ -1,9 +1,14 @@
 package parser
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
 	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/lsg93/org-chart-parser/internal/model"
 )
@@ -58,6 +63,33 @@ func TestParsesOrgChartTextSuccesfully(t *testing.T) {
 				model.Employee{Id: 3, Name: "Joshua", ManagerId: 2},
 			},
 		},
+		"with optional title and department columns": {
+			input: `| ID | Name | Title | Manager ID | Department |
+			| 1 | Lawrence | CEO | | Board |
+			| 2 | Adrian | | 1 | Ops |`,
+			expectedResult: model.OrganisationChart{
+				model.Employee{Id: 1, Name: "Lawrence", ManagerId: 0, Title: "CEO", Department: "Board"},
+				model.Employee{Id: 2, Name: "Adrian", ManagerId: 1, Department: "Ops"},
+			},
+		},
+		"with dotted line and interim managers": {
+			input: `| ID | Name | Manager ID | Dotted Manager IDs | Interim Manager IDs |
+			| 1 | Lawrence | | | |
+			| 2 | Adrian | 1 | | |
+			| 3 | Joshua | 2 | 1, 4 | |
+			| 4 | Natalie | 1 | | 2 |`,
+			expectedResult: model.OrganisationChart{
+				model.Employee{Id: 1, Name: "Lawrence", ManagerId: 0},
+				model.Employee{Id: 2, Name: "Adrian", ManagerId: 1},
+				model.Employee{Id: 3, Name: "Joshua", ManagerId: 2, Relationships: []model.Relationship{
+					{ManagerId: 1, Type: model.DottedLine},
+					{ManagerId: 4, Type: model.DottedLine},
+				}},
+				model.Employee{Id: 4, Name: "Natalie", ManagerId: 1, Relationships: []model.Relationship{
+					{ManagerId: 2, Type: model.Interim},
+				}},
+			},
+		},
 		"with leading whitespace": {
 			input: `
 			| ID | Name | Manager ID |
@@ -69,6 +101,94 @@ func TestParsesOrgChartTextSuccesfully(t *testing.T) {
 				model.Employee{Id: 3, Name: "Joshua", ManagerId: 2},
 			},
 		},
+		"with a markdown separator row and alignment colons": {
+			input: `| ID | Name | Manager ID |
+			|:---|:----:|---:|
+			| 1 | Lawrence | |
+			| 2 | Adrian | 1 |`,
+			expectedResult: model.OrganisationChart{
+				model.Employee{Id: 1, Name: "Lawrence", ManagerId: 0},
+				model.Employee{Id: 2, Name: "Adrian", ManagerId: 1},
+			},
+		},
+		"with escaped pipes in names": {
+			input: `| ID | Name | Manager ID |
+			| 1 | Lawrence \| CEO | |
+			| 2 | Adrian\|Ops | 1 |`,
+			expectedResult: model.OrganisationChart{
+				model.Employee{Id: 1, Name: "Lawrence | CEO", ManagerId: 0},
+				model.Employee{Id: 2, Name: "Adrian|Ops", ManagerId: 1},
+			},
+		},
+		"without leading and trailing pipes": {
+			input: `ID | Name | Manager ID
+			--- | --- | ---
+			1 | Lawrence | |
+			2 | Adrian | 1`,
+			expectedResult: model.OrganisationChart{
+				model.Employee{Id: 1, Name: "Lawrence", ManagerId: 0},
+				model.Employee{Id: 2, Name: "Adrian", ManagerId: 1},
+			},
+		},
+		"from a larger markdown document": {
+			input: `# Engineering
+
+			Our org chart, last updated in March. The | pipe in this sentence isn't a table.
+
+			| Team | Lead |
+			|------|------|
+			| Web  | Ada  |
+
+			| ID | Name | Manager ID |
+			|----|------|------------|
+			| 1 | Lawrence | |
+			| 2 | Adrian | 1 |
+
+			More text afterwards.
+
+			| ID | Name | Manager ID |
+			|----|------|------------|
+			| 3 | Joshua | 2 |`,
+			expectedResult: model.OrganisationChart{
+				model.Employee{Id: 1, Name: "Lawrence", ManagerId: 0},
+				model.Employee{Id: 2, Name: "Adrian", ManagerId: 1},
+			},
+		},
+		"from a markdown document with prose straight after the table": {
+			input: `Intro text.
+			| ID | Name | Manager ID |
+			| 1 | Lawrence | |
+			That's everyone.`,
+			expectedResult: model.OrganisationChart{
+				model.Employee{Id: 1, Name: "Lawrence", ManagerId: 0},
+			},
+		},
+		"with effective dates": {
+			input: `| ID | Name | Manager ID | Start Date | End Date |
+			| 1 | Lawrence | | | |
+			| 2 | Adrian | 1 | | 2025-06-30 |
+			| 2 | Adrian | 3 | 2025-07-01 | |`,
+			expectedResult: model.OrganisationChart{
+				model.Employee{Id: 1, Name: "Lawrence", ManagerId: 0},
+				model.Employee{Id: 2, Name: "Adrian", ManagerId: 1, Effective: model.DateRange{To: time.Date(2025, time.June, 30, 0, 0, 0, 0, time.UTC)}},
+				model.Employee{Id: 2, Name: "Adrian", ManagerId: 3, Effective: model.DateRange{From: time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC)}},
+			},
+		},
+		"with vacancies": {
+			input: `| ID | Name | Manager ID | Vacant |
+			| 1 | Lawrence | | |
+			| 2 | TBH - Senior Engineer | 1 | yes |
+			| 3 | Head of Ops | 1 | Yes |
+			| 4 | Vacant Jones | 1 | |
+			| 5 | Tbd Smith | 1 | no |`,
+			expectedResult: model.OrganisationChart{
+				model.Employee{Id: 1, Name: "Lawrence", ManagerId: 0},
+				model.Employee{Id: 2, Name: "TBH - Senior Engineer", ManagerId: 1, Vacant: true},
+				model.Employee{Id: 3, Name: "Head of Ops", ManagerId: 1, Vacant: true},
+				model.Employee{Id: 4, Name: "Vacant Jones", ManagerId: 1},
+				model.Employee{Id: 5, Name: "Tbd Smith", ManagerId: 1},
+			},
+		},
 	}
 
 	for desc, tc := range testCases {
@@ -81,13 +201,108 @@ func TestParsesOrgChartTextSuccesfully(t *testing.T) {
 				t.Fatalf("There was an error '%s' parsing the provided the input data.", err)
 			}
 
-			if slices.Equal(result, tc.expectedResult) == false {
+			if reflect.DeepEqual(result, tc.expectedResult) == false {
 				t.Errorf("The result %v was not the same as the expected result %v", result, tc.expectedResult)
 			}
 		})
 	}
 }
 
+func TestParsesCommentsAndFrontMatter(t *testing.T) {
+	type testCase struct {
+		input            string
+		expectedResult   model.OrganisationChart
+		expectedMetadata model.ChartMetadata
+	}
+
+	testCases := map[string]testCase{
+		"with comments": {
+			input: `# Checked in from the HR export.
+			| ID | Name | Manager ID |
+			# Lawrence is the root.
+			| 1 | Lawrence | |
+			| 2 | Adrian | 1 |`,
+			expectedResult: model.OrganisationChart{
+				model.Employee{Id: 1, Name: "Lawrence", ManagerId: 0},
+				model.Employee{Id: 2, Name: "Adrian", ManagerId: 1},
+			},
+		},
+		"with a row starting with a hash": {
+			input: `Name | Manager ID | ID
+			Lawrence | | 1
+			# Still a comment, as it has no pipes.
+			#1 Fan | 1 | 2`,
+			expectedResult: model.OrganisationChart{
+				model.Employee{Id: 1, Name: "Lawrence", ManagerId: 0},
+				model.Employee{Id: 2, Name: "#1 Fan", ManagerId: 1},
+			},
+		},
+		"with front matter": {
+			input: `---
+			name: Avengers Initiative
+			# Updated monthly.
+			As-Of: 2026-09-01
+			source: HR export
+			format: JSON
+			---
+			| ID | Name | Manager ID |
+			| 1 | Lawrence | |`,
+			expectedResult: model.OrganisationChart{
+				model.Employee{Id: 1, Name: "Lawrence", ManagerId: 0},
+			},
+			expectedMetadata: model.ChartMetadata{
+				Name:   "Avengers Initiative",
+				AsOf:   time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC),
+				Source: "HR export",
+				Format: "json",
+			},
+		},
+		"with a partial front matter block": {
+			input: `---
+			name: Avengers: Earth's Mightiest
+			---
+			| ID | Name | Manager ID |
+			| 1 | Lawrence | |`,
+			expectedResult: model.OrganisationChart{
+				model.Employee{Id: 1, Name: "Lawrence", ManagerId: 0},
+			},
+			expectedMetadata: model.ChartMetadata{Name: "Avengers: Earth's Mightiest"},
+		},
+		"with front matter keys the parser doesn't use": {
+			input: `---
+			title: The Avengers
+			name: Avengers Initiative
+			owner: Nick Fury
+			---
+			| ID | Name | Manager ID |
+			| 1 | Lawrence | |`,
+			expectedResult: model.OrganisationChart{
+				model.Employee{Id: 1, Name: "Lawrence", ManagerId: 0},
+			},
+			expectedMetadata: model.ChartMetadata{Name: "Avengers Initiative"},
+		},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			parser := setupParser(tc.input, t)
+			result, err := parser.Parse()
+
+			if err != nil {
+				t.Fatalf("An error '%s' was returned when none was expected", err)
+			}
+
+			if !reflect.DeepEqual(result, tc.expectedResult) {
+				t.Errorf("The chart %v was not equal to the expected chart %v", result, tc.expectedResult)
+			}
+
+			if parser.Metadata() != tc.expectedMetadata {
+				t.Errorf("The metadata %v was not equal to the expected metadata %v", parser.Metadata(), tc.expectedMetadata)
+			}
+		})
+	}
+}
+
 func TestFailsToParseWhenOrgChartTextIsInvalid(t *testing.T) {
 	type testCase struct {
 		input         string
@@ -115,6 +330,16 @@ func TestFailsToParseWhenOrgChartTextIsInvalid(t *testing.T) {
 			`,
 			expectedError: errParserInvalidHeader,
 		},
+		"with unknown header fields": {
+			input: `| ID | Name | Manager ID | Salary |
+			| 1 | Lawrence | | 100 |`,
+			expectedError: errParserInvalidHeader,
+		},
+		"with repeated header fields": {
+			input: `| ID | Name | Manager ID | Name |
+			| 1 | Lawrence | | Lawrence |`,
+			expectedError: errParserInvalidHeader,
+		},
 		"with missing ID field": {
 			input: `| ID | Name | Manager ID |
 			| 1 | Lawrence | |
@@ -131,11 +356,81 @@ func TestFailsToParseWhenOrgChartTextIsInvalid(t *testing.T) {
 			| 1 | Lawrence | A |`,
 			expectedError: errParserInvalidIdField,
 		},
+		"with non numeric dotted manager ID": {
+			input: `| ID | Name | Manager ID | Dotted Manager IDs |
+			| 1 | Lawrence | | A |`,
+			expectedError: errParserInvalidIdField,
+		},
+		"with self referential dotted manager": {
+			input: `| ID | Name | Manager ID | Dotted Manager IDs |
+			| 1 | Lawrence | | 1 |`,
+			expectedError: errParserInvalidIdField,
+		},
 		"with self referential data": {
 			input: `| ID | Name | Manager ID |
 			| 1 | Lawrence | 1 |`,
 			expectedError: errParserInvalidIdField,
 		},
+		"with self referential data written differently": {
+			input: `| ID | Name | Manager ID |
+			| 01 | Lawrence | 1 |`,
+			expectedError: errParserInvalidIdField,
+		},
+		"with a header without pipes": {
+			input:         "ID Name Manager",
+			expectedError: errParserInvalidHeader,
+		},
+		"with a separator row of the wrong width": {
+			input: `| ID | Name | Manager ID |
+			|---|---|`,
+			expectedError: errParserInvalidLineLength,
+		},
+		"with a document that has no matching table": {
+			input: `# Engineering
+
+			| Team | Lead |
+			|------|------|
+			| Web  | Ada  |`,
+			expectedError: errParserInvalidHeader,
+		},
+		"with only comments": {
+			input: `# Nothing here yet.
+			# Still nothing.`,
+			expectedError: errParserEmptyInput,
+		},
+		"with unclosed front matter": {
+			input: `---
+			name: Avengers Initiative
+			| ID | Name | Manager ID |`,
+			expectedError: errParserInvalidMetadata,
+		},
+		"with an invalid as of date": {
+			input: `---
+			as of: 1st September
+			---
+			| ID | Name | Manager ID |`,
+			expectedError: errParserInvalidMetadata,
+		},
+		"with an invalid start date": {
+			input: `| ID | Name | Manager ID | Start Date |
+			| 1 | Lawrence | | 1st July |`,
+			expectedError: errParserInvalidDateField,
+		},
+		"with a start date after the end date": {
+			input: `| ID | Name | Manager ID | Start Date | End Date |
+			| 1 | Lawrence | | 2025-07-01 | 2025-06-30 |`,
+			expectedError: errParserInvalidDateField,
+		},
+		"with an invalid vacant field": {
+			input: `| ID | Name | Manager ID | Vacant |
+			| 1 | Lawrence | | maybe |`,
+			expectedError: errParserInvalidVacantField,
+		},
+		"with a row without pipes": {
+			input: `| ID | Name | Manager ID |
+			1 Lawrence`,
+			expectedError: errParserInvalidLineLength,
+		},
 	}
 
 	for desc, tc := range testCases {
@@ -148,9 +443,74 @@ func TestFailsToParseWhenOrgChartTextIsInvalid(t *testing.T) {
 				t.Fatalf("An error should have occurred while attempting to parse the data, but none did.")
 			}
 
-			if err != tc.expectedError {
+			if !errors.Is(err, tc.expectedError) {
 				t.Errorf("The expected error '%v' was not the same as the returned error '%v'.", err, tc.expectedError)
 			}
 		})
 	}
 }
+
+func TestParsingStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := setupParser("| ID | Name | Manager ID |\n| 1 | Lawrence | |", t).ParseContext(ctx)
+
+	if !errors.Is(err, errParserCancelled) || !errors.Is(err, context.Canceled) {
+		t.Errorf("The error '%v' was returned, but it was not the expected error '%v'", err, errParserCancelled)
+	}
+}
+
+// Parse should never panic - every input either gives a chart or one of the parser's own errors,
+// and any chart it does give should survive being written out and parsed again.
+func FuzzParse(f *testing.F) {
+	seeds := []string{
+		"|ID|Name|Manager ID|\n|1|Lawrence||\n|2|Adrian|1|\n|3|Joshua|2|",
+		"| ID | Name | Title | Manager ID | Department |\n| 1 | Lawrence | CEO | | Board |\n| 2 | Adrian | | 1 | Ops |",
+		"| ID | Name | Manager ID | Dotted Manager IDs | Interim Manager IDs |\n| 1 | Lawrence | | | |\n| 3 | Joshua | 2 | 1, 4 | |",
+		"|ID|Name|Manager ID|\n|  |  |  |\n|01|Lawrence|1|",
+		"ID|Name|Manager ID\n1|Lawrence|",
+		"---\nname: Avengers\nas of: 2026-09-01\n---\n# comment\n|ID|Name|Manager ID|\n|1|Lawrence||",
+		"|ID|Name|Manager ID|Start Date|End Date|\n|1|Lawrence||2025-01-01|2025-12-31|",
+		"# Chart\n\n| ID | Name | Manager ID |\n|:---|:---:|---:|\n| 1 | Law\\|rence | |\n\nAfter",
+		"|ID|Name|Manager ID|Vacant|\n|1|Lawrence|||\n|2|TBH - Engineer|1||\n|3|Head of Ops|1|yes|",
+		"|\n|",
+		"",
+	}
+
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	parserErrors := []error{errParserScanError, errParserEmptyInput, errParserInvalidHeader, errParserInvalidIdField, errParserInvalidLineLength, errParserInvalidDateField, errParserInvalidVacantField, errParserInvalidMetadata}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		chart, err := setupParser(input, t).Parse()
+
+		if err != nil {
+			if !slices.ContainsFunc(parserErrors, func(target error) bool { return errors.Is(err, target) }) {
+				t.Fatalf("The error '%v' is not one of the parser's errors", err)
+			}
+
+			return
+		}
+
+		var output bytes.Buffer
+		writer, _ := NewOrganisationChartWriter(&output, FormatTable)
+
+		if err := writer.Write(chart); err != nil {
+			t.Fatalf("An error '%s' was returned when writing the chart", err)
+		}
+
+		result, err := setupParser(output.String(), t).Parse()
+
+		// A header with no rows parses to an empty chart, which the writer still writes a header for.
+		if err != nil {
+			t.Fatalf("An error '%s' was returned parsing the written chart '%s'", err, output.String())
+		}
+
+		if !reflect.DeepEqual(result, chart) {
+			t.Fatalf("The chart %#v changed to %#v after being written out as '%s'", chart, result, output.String())
+		}
+	})
+}
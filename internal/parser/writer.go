@@ -0,0 +1,175 @@
+package parser
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+// Output formats a chart can be written in.
+const (
+	FormatTable = "table" // the same pipe table the parser reads
+	FormatJSON  = "json"
+)
+
+var Formats = []string{FormatTable, FormatJSON}
+
+var (
+	errWriterUnknownFormat = errors.New("The output format provided is not supported - use table or json.")
+)
+
+// The other side of OrganisationChartParser - turns a chart back into text.
+type OrganisationChartWriter interface {
+	Write(chart model.OrganisationChart) error
+}
+
+type orgChartTableWriter struct {
+	output io.Writer
+}
+
+type orgChartJSONWriter struct {
+	output io.Writer
+}
+
+func NewOrganisationChartWriter(output io.Writer, format string) (OrganisationChartWriter, error) {
+	switch format {
+	case FormatTable:
+		return &orgChartTableWriter{output: output}, nil
+	case FormatJSON:
+		return &orgChartJSONWriter{output: output}, nil
+	default:
+		return nil, errWriterUnknownFormat
+	}
+}
+
+// Writes an aligned pipe table. Optional columns are only included when at least one employee has a value for them.
+func (writer *orgChartTableWriter) Write(chart model.OrganisationChart) error {
+	columns := slices.Clone(requiredColumns)
+
+	for _, column := range optionalColumns {
+		if slices.ContainsFunc(chart, func(e model.Employee) bool { return columnValues[column](e) != "" }) {
+			columns = append(columns, column)
+		}
+	}
+
+	headings := make([]string, 0, len(columns))
+
+	for _, column := range columns {
+		headings = append(headings, columnHeadings[column])
+	}
+
+	rows := [][]string{headings}
+
+	for _, employee := range chart {
+		row := make([]string, 0, len(columns))
+
+		for _, column := range columns {
+			row = append(row, columnValues[column](employee))
+		}
+
+		rows = append(rows, row)
+	}
+
+	_, err := io.WriteString(writer.output, formatTable(rows))
+
+	return err
+}
+
+func (writer *orgChartJSONWriter) Write(chart model.OrganisationChart) error {
+	encoder := json.NewEncoder(writer.output)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(chart)
+}
+
+// Pads every cell to the width of the widest cell in its column. Pipes inside a cell are escaped (\|) so they don't split it.
+func formatTable(rows [][]string) string {
+	widths := make([]int, len(rows[0]))
+
+	for _, row := range rows {
+		for i, cell := range row {
+			row[i] = strings.ReplaceAll(cell, "|", `\|`)
+			widths[i] = max(widths[i], len([]rune(row[i])))
+		}
+	}
+
+	var builder strings.Builder
+
+	for _, row := range rows {
+		builder.WriteString("|")
+
+		for i, cell := range row {
+			builder.WriteString(" " + cell + strings.Repeat(" ", widths[i]-len([]rune(cell))) + " |")
+		}
+
+		builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
+
+// How each column is written in a header. The parser doesn't mind about case, but this is how they look nicest.
+var columnHeadings = map[string]string{
+	columnId:         "ID",
+	columnName:       "Name",
+	columnManagerId:  "Manager ID",
+	columnTitle:      "Title",
+	columnDepartment: "Department",
+	columnDotted:     "Dotted Manager IDs",
+	columnInterim:    "Interim Manager IDs",
+	columnStartDate:  "Start Date",
+	columnEndDate:    "End Date",
+	columnVacant:     "Vacant",
+}
+
+// What goes in each column for an employee - the opposite of marshalLine.
+var columnValues = map[string]func(model.Employee) string{
+	columnId:   func(e model.Employee) string { return strconv.Itoa(e.Id) },
+	columnName: func(e model.Employee) string { return e.Name },
+	columnManagerId: func(e model.Employee) string {
+		if e.ManagerId == 0 {
+			return ""
+		}
+
+		return strconv.Itoa(e.ManagerId)
+	},
+	columnTitle:      func(e model.Employee) string { return e.Title },
+	columnDepartment: func(e model.Employee) string { return e.Department },
+	columnDotted:     func(e model.Employee) string { return joinManagerIds(e, model.DottedLine) },
+	columnInterim:    func(e model.Employee) string { return joinManagerIds(e, model.Interim) },
+	columnStartDate:  func(e model.Employee) string { return formatDate(e.Effective.From) },
+	columnEndDate:    func(e model.Employee) string { return formatDate(e.Effective.To) },
+	columnVacant: func(e model.Employee) string {
+		if e.Vacant {
+			return "yes"
+		}
+
+		return ""
+	},
+}
+
+func joinManagerIds(employee model.Employee, relationshipType model.RelationshipType) string {
+	ids := make([]string, 0)
+
+	for _, relationship := range employee.Relationships {
+		if relationship.Type == relationshipType {
+			ids = append(ids, strconv.Itoa(relationship.ManagerId))
+		}
+	}
+
+	return strings.Join(ids, ", ")
+}
+
+func formatDate(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+
+	return date.Format(time.DateOnly)
+}
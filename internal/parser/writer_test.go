@@ -0,0 +1,129 @@
+package parser
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+func TestWritesOrgChartsInEachFormat(t *testing.T) {
+	type testCase struct {
+		format         string
+		chart          model.OrganisationChart
+		expectedOutput string
+	}
+
+	testCases := map[string]testCase{
+		"as a table": {
+			format: FormatTable,
+			chart: model.OrganisationChart{
+				{Id: 1, Name: "Lawrence"},
+				{Id: 2, Name: "Adrian", ManagerId: 1},
+				{Id: 10, Name: "Jo", ManagerId: 2},
+			},
+			expectedOutput: "| ID | Name     | Manager ID |\n" +
+				"| 1  | Lawrence |            |\n" +
+				"| 2  | Adrian   | 1          |\n" +
+				"| 10 | Jo       | 2          |\n",
+		},
+		"as a table with optional columns": {
+			format: FormatTable,
+			chart: model.OrganisationChart{
+				{Id: 1, Name: "Lawrence", Department: "Board"},
+				{Id: 2, Name: "Adrian", ManagerId: 1, Relationships: []model.Relationship{{ManagerId: 3, Type: model.Interim}}},
+				{Id: 3, Name: "Natalie", ManagerId: 1},
+			},
+			expectedOutput: "| ID | Name     | Manager ID | Department | Interim Manager IDs |\n" +
+				"| 1  | Lawrence |            | Board      |                     |\n" +
+				"| 2  | Adrian   | 1          |            | 3                   |\n" +
+				"| 3  | Natalie  | 1          |            |                     |\n",
+		},
+		"as json": {
+			format: FormatJSON,
+			chart: model.OrganisationChart{
+				{Id: 1, Name: "Lawrence", Title: "CEO"},
+				{Id: 2, Name: "Adrian", ManagerId: 1, Relationships: []model.Relationship{{ManagerId: 1, Type: model.DottedLine}}},
+			},
+			expectedOutput: `[
+  {
+    "id": 1,
+    "name": "Lawrence",
+    "title": "CEO"
+  },
+  {
+    "id": 2,
+    "name": "Adrian",
+    "managerId": 1,
+    "relationships": [
+      {
+        "managerId": 1,
+        "type": "dotted"
+      }
+    ]
+  }
+]
+`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var output bytes.Buffer
+			writer, err := NewOrganisationChartWriter(&output, tc.format)
+
+			if err != nil {
+				t.Fatalf("An error '%s' was returned when none was expected", err)
+			}
+
+			if err := writer.Write(tc.chart); err != nil {
+				t.Fatalf("An error '%s' was returned when none was expected", err)
+			}
+
+			if output.String() != tc.expectedOutput {
+				t.Errorf("The received output '%s' was not equal to the expected output '%s'", output.String(), tc.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestWrittenTablesCanBeParsedAgain(t *testing.T) {
+	chart := model.OrganisationChart{
+		{Id: 1, Name: "Lawrence", Title: "CEO", Department: "Board"},
+		{Id: 2, Name: "Adrian", ManagerId: 1, Title: "CTO"},
+		{Id: 3, Name: "Joshua", ManagerId: 2, Relationships: []model.Relationship{
+			{ManagerId: 1, Type: model.DottedLine},
+			{ManagerId: 4, Type: model.DottedLine},
+			{ManagerId: 2, Type: model.Interim},
+		}},
+		{Id: 4, Name: "Natalie | Ops", ManagerId: 1},
+		{Id: 5, Name: `Back\slash \|`, ManagerId: 4, Effective: model.DateRange{From: time.Date(2025, time.July, 1, 0, 0, 0, 0, time.UTC)}},
+		{Id: 6, Name: "TBH - Engineer", ManagerId: 2, Vacant: true},
+		{Id: 7, Name: "Head of Ops", ManagerId: 1, Vacant: true},
+	}
+
+	var output bytes.Buffer
+	writer, _ := NewOrganisationChartWriter(&output, FormatTable)
+
+	if err := writer.Write(chart); err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	result, err := setupParser(output.String(), t).Parse()
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	if !reflect.DeepEqual(result, chart) {
+		t.Errorf("The parsed chart %v was not the same as the written chart %v", result, chart)
+	}
+}
+
+func TestRejectsUnknownOutputFormats(t *testing.T) {
+	if _, err := NewOrganisationChartWriter(&bytes.Buffer{}, "csv"); err != errWriterUnknownFormat {
+		t.Errorf("The error '%v' was returned, but it was not the expected error '%v'", err, errWriterUnknownFormat)
+	}
+}
@@ -0,0 +1,389 @@
+// Package server answers chart queries over HTTP with JSON, so other tools can use the analysis without shelling out to the CLI.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/lsg93/org-chart-parser/internal/analysis"
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+// How long a request gets before it's abandoned, unless the server is told otherwise.
+const DefaultTimeout = 10 * time.Second
+
+var (
+	errServerNoCharts         = errors.New("The server needs at least one chart to serve.")
+	errServerDuplicateChart   = errors.New("More than one chart has the same name - each chart needs its own name to be served.")
+	errServerUnknownChart     = errors.New("No chart with that name is being served.")
+	errServerMissingParameter = errors.New("A required query parameter is missing.")
+	errServerInvalidParameter = errors.New("One of the query parameters has an invalid value.")
+	errServerInvalidReload    = errors.New("The new version of the chart isn't valid:")
+)
+
+// A chart to serve, under the name used in its URLs (e.g. /charts/avengers/stats).
+type Chart struct {
+	Name     string
+	Chart    model.OrganisationChart
+	Metadata model.ChartMetadata
+	Options  []analysis.AnalyserOption // on top of the server's own, for this chart only - e.g. the tables from its index
+}
+
+// The analyser methods the endpoints use - the analyser type itself isn't exported, so it's held through this.
+type chartQueries interface {
+	PathsContext(ctx context.Context, name1 string, name2 string) ([]analysis.PathResult, error)
+	FindOne(selector string) (model.Employee, error)
+	Employee(id int) (model.Employee, bool)
+	ChainOfCommand(id int) ([]analysis.ChainLink, error)
+	Reports(id int, depth int) ([]analysis.ReportLevel, error)
+	LowestCommonManager(id1 int, id2 int) (model.Employee, error)
+	Search(query string) []model.Employee
+	Stats() analysis.ChartStats
+	Validate() error
+}
+
+type servedChart struct {
+	metadata model.ChartMetadata
+	queries  chartQueries
+}
+
+type Server struct {
+	charts  map[string]*atomic.Pointer[servedChart] // swapped whole on reload, so a request never sees half of each version
+	names   []string                                // sorted, for listing
+	timeout time.Duration
+	handler http.Handler
+	opts    []analysis.AnalyserOption
+}
+
+// Every chart gets an analyser up front, built with the same options, so requests don't pay for it.
+// Analysers don't change once they're built, so requests can share them safely.
+func NewServer(charts []Chart, timeout time.Duration, opts ...analysis.AnalyserOption) (*Server, error) {
+	if len(charts) == 0 {
+		return nil, errServerNoCharts
+	}
+
+	server := &Server{charts: make(map[string]*atomic.Pointer[servedChart], len(charts)), timeout: timeout, opts: opts}
+
+	for _, chart := range charts {
+		if _, ok := server.charts[chart.Name]; ok {
+			return nil, errServerDuplicateChart
+		}
+
+		server.charts[chart.Name] = new(atomic.Pointer[servedChart])
+		server.charts[chart.Name].Store(server.serve(chart))
+		server.names = append(server.names, chart.Name)
+	}
+
+	slices.Sort(server.names)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /charts", server.listCharts)
+	server.handle(mux, "path", pathHandler)
+	server.handle(mux, "lowest-common-manager", lowestCommonManagerHandler)
+	server.handle(mux, "chain", chainHandler)
+	server.handle(mux, "reports", reportsHandler)
+	server.handle(mux, "search", searchHandler)
+	server.handle(mux, "stats", statsHandler)
+
+	// The body has to be written up front, so it's built by hand rather than through writeError.
+	timeoutBody, _ := json.Marshal(errorResponse{Error: "The request took too long to answer."})
+	server.handler = http.TimeoutHandler(mux, timeout, string(timeoutBody))
+
+	return server, nil
+}
+
+// Swaps in a new version of a chart that's already being served. Requests already in flight finish with the version they started with.
+// The new version has to pass validation first - if it doesn't, the previous version carries on being served and the problems are returned.
+func (s *Server) Reload(chart Chart) error {
+	current, ok := s.charts[chart.Name]
+
+	if !ok {
+		return fmt.Errorf("%w '%s' isn't one of them.", errServerUnknownChart, chart.Name)
+	}
+
+	updated := s.serve(chart)
+
+	if err := updated.queries.Validate(); err != nil {
+		return fmt.Errorf("%w\n%w", errServerInvalidReload, err)
+	}
+
+	current.Store(updated)
+
+	return nil
+}
+
+func (s *Server) serve(chart Chart) *servedChart {
+	opts := append(slices.Clone(s.opts), chart.Options...)
+
+	return &servedChart{metadata: chart.Metadata, queries: analysis.NewOrganisationChartAnalyser(io.Discard, chart.Chart, opts...)}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.ServeHTTP(w, r)
+}
+
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(ctx, listener)
+}
+
+// Serves until the context is cancelled, then stops taking new requests and waits for the ones in flight to finish.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	server := &http.Server{
+		Handler:           s,
+		ReadHeaderTimeout: s.timeout,
+		ReadTimeout:       s.timeout,
+		// Longer than the handler timeout, so there's still time to write the response saying it timed out.
+		WriteTimeout: s.timeout + time.Second,
+		IdleTimeout:  time.Minute,
+	}
+
+	errs := make(chan error, 1)
+
+	go func() {
+		errs <- server.Serve(listener)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	// Requests in flight get as long as they would have had anyway.
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+type chartSummary struct {
+	Name     string              `json:"name"`
+	Metadata model.ChartMetadata `json:"metadata"`
+}
+
+func (s *Server) listCharts(w http.ResponseWriter, r *http.Request) {
+	charts := make([]chartSummary, 0, len(s.names))
+
+	for _, name := range s.names {
+		charts = append(charts, chartSummary{Name: name, Metadata: s.charts[name].Load().metadata})
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"charts": charts})
+}
+
+// Each endpoint works on one chart, picked by the {chart} part of the URL, and answers from the query string.
+// The context is the request's, so it's cancelled when the request times out or the client goes away.
+type chartHandler func(ctx context.Context, chart servedChart, query url.Values) (any, error)
+
+func (s *Server) handle(mux *http.ServeMux, endpoint string, handler chartHandler) {
+	mux.HandleFunc("GET /charts/{chart}/"+endpoint, func(w http.ResponseWriter, r *http.Request) {
+		chart, ok := s.charts[r.PathValue("chart")]
+
+		if !ok {
+			writeError(w, errServerUnknownChart)
+			return
+		}
+
+		response, err := handler(r.Context(), *chart.Load(), r.URL.Query())
+
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, response)
+	})
+}
+
+// A path with the employees along it, as well as their IDs.
+type pathResponse struct {
+	analysis.PathResult
+	Employees []model.Employee `json:"employees"`
+}
+
+func pathHandler(ctx context.Context, chart servedChart, query url.Values) (any, error) {
+	from, to := parameter(query, "from"), parameter(query, "to")
+
+	if from == "" || to == "" {
+		return nil, missingParameter("from", "to")
+	}
+
+	// Searches are the one query that can take a long time on a big chart, so they stop once the request has timed out.
+	paths, err := chart.queries.PathsContext(ctx, from, to)
+
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]pathResponse, 0, len(paths))
+
+	for _, path := range paths {
+		response := pathResponse{PathResult: path, Employees: make([]model.Employee, 0, len(path.Ids))}
+
+		for _, id := range path.Ids {
+			employee, _ := chart.queries.Employee(id)
+			response.Employees = append(response.Employees, employee)
+		}
+
+		responses = append(responses, response)
+	}
+
+	return map[string]any{"paths": responses}, nil
+}
+
+// Takes exactly two employees, e.g. ?employee=Hawkeye&employee=Hit%20Girl.
+func lowestCommonManagerHandler(ctx context.Context, chart servedChart, query url.Values) (any, error) {
+	selectors := query["employee"]
+
+	if len(selectors) != 2 {
+		return nil, fmt.Errorf("%w Give exactly two employees, e.g. ?employee=Hawkeye&employee=Hit Girl.", errServerInvalidParameter)
+	}
+
+	employees := make([]model.Employee, 0, 2)
+
+	for _, selector := range selectors {
+		employee, err := chart.queries.FindOne(selector)
+
+		if err != nil {
+			return nil, err
+		}
+
+		employees = append(employees, employee)
+	}
+
+	manager, err := chart.queries.LowestCommonManager(employees[0].Id, employees[1].Id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{"employees": employees, "manager": manager}, nil
+}
+
+func chainHandler(ctx context.Context, chart servedChart, query url.Values) (any, error) {
+	employee, err := findEmployee(chart, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := chart.queries.ChainOfCommand(employee.Id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{"chain": chain}, nil
+}
+
+// ?depth= limits how many levels come back - 0, the default, means no limit.
+func reportsHandler(ctx context.Context, chart servedChart, query url.Values) (any, error) {
+	employee, err := findEmployee(chart, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	depth := 0
+
+	if value := query.Get("depth"); value != "" {
+		if depth, err = strconv.Atoi(value); err != nil {
+			return nil, fmt.Errorf("%w depth should be a whole number.", errServerInvalidParameter)
+		}
+	}
+
+	levels, err := chart.queries.Reports(employee.Id, depth)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{"employee": employee, "levels": levels}, nil
+}
+
+func searchHandler(ctx context.Context, chart servedChart, query url.Values) (any, error) {
+	q := parameter(query, "q")
+
+	if q == "" {
+		return nil, missingParameter("q")
+	}
+
+	return map[string]any{"employees": chart.queries.Search(q)}, nil
+}
+
+func statsHandler(ctx context.Context, chart servedChart, query url.Values) (any, error) {
+	return chart.queries.Stats(), nil
+}
+
+func findEmployee(chart servedChart, query url.Values) (model.Employee, error) {
+	selector := parameter(query, "employee")
+
+	if selector == "" {
+		return model.Employee{}, missingParameter("employee")
+	}
+
+	return chart.queries.FindOne(selector)
+}
+
+func parameter(query url.Values, name string) string {
+	return strings.TrimSpace(query.Get(name))
+}
+
+func missingParameter(names ...string) error {
+	return fmt.Errorf("%w Include %s.", errServerMissingParameter, strings.Join(names, " and "))
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// Problems with the request are the client's to fix, while problems with the chart itself can't be fixed by asking differently.
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+
+	switch {
+	case errors.Is(err, errServerUnknownChart), analysis.IsNotFound(err):
+		status = http.StatusNotFound
+	case errors.Is(err, errServerMissingParameter), errors.Is(err, errServerInvalidParameter), analysis.IsInvalidRequest(err):
+		status = http.StatusBadRequest
+	case analysis.IsBrokenChart(err):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		status = http.StatusServiceUnavailable
+	}
+
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, response any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	// Headers have gone by now, so there's nothing useful to do if this fails.
+	_ = json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,323 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+var avengersChart = Chart{
+	Name: "avengers",
+	Chart: model.OrganisationChart{
+		{Id: 1, Name: "Nick Fury"},
+		{Id: 2, Name: "Iron Man", ManagerId: 1},
+		{Id: 3, Name: "Captain Marvel", ManagerId: 1},
+		{Id: 6, Name: "Black Widow", ManagerId: 2},
+		{Id: 16, Name: "Hawkeye", ManagerId: 6},
+		{Id: 17, Name: "Scarlet Witch", ManagerId: 6},
+		{Id: 20, Name: "Hawkeye", ManagerId: 3},
+	},
+	Metadata: model.ChartMetadata{Name: "Avengers Initiative"},
+}
+
+var brokenChart = Chart{
+	Name: "broken",
+	Chart: model.OrganisationChart{
+		{Id: 1, Name: "Loop A", ManagerId: 2},
+		{Id: 2, Name: "Loop B", ManagerId: 1},
+	},
+}
+
+func setupTestServer(t *testing.T) *httptest.Server {
+	server, err := NewServer([]Chart{avengersChart, brokenChart}, DefaultTimeout)
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	testServer := httptest.NewServer(server)
+	t.Cleanup(testServer.Close)
+
+	return testServer
+}
+
+// Requests a path and decodes the JSON response into a generic value, so tests can pick out the parts they care about.
+func get(t *testing.T, server *httptest.Server, path string) (int, map[string]any) {
+	response, err := http.Get(server.URL + path)
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	defer response.Body.Close()
+
+	if contentType := response.Header.Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("The content type '%s' was not JSON.", contentType)
+	}
+
+	body := make(map[string]any)
+
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		t.Fatalf("The response body could not be decoded: %s", err)
+	}
+
+	return response.StatusCode, body
+}
+
+// Picks the names out of a list of employees in a response.
+func names(value any) []string {
+	names := make([]string, 0)
+
+	for _, item := range value.([]any) {
+		names = append(names, item.(map[string]any)["name"].(string))
+	}
+
+	return names
+}
+
+func TestServingChartQueries(t *testing.T) {
+	type testCase struct {
+		path     string
+		received func(body map[string]any) string // picks out the part of the response being checked
+		wanted   string
+	}
+
+	testCases := map[string]testCase{
+		"listing charts": {
+			path: "/charts",
+			received: func(body map[string]any) string {
+				charts := body["charts"].([]any)
+				return charts[0].(map[string]any)["name"].(string) + ", " + charts[1].(map[string]any)["name"].(string)
+			},
+			wanted: "avengers, broken",
+		},
+		"a path": {
+			path: "/charts/avengers/path?from=Scarlet+Witch&to=Captain+Marvel",
+			received: func(body map[string]any) string {
+				return strings.Join(names(body["paths"].([]any)[0].(map[string]any)["employees"]), " > ")
+			},
+			wanted: "Scarlet Witch > Black Widow > Iron Man > Nick Fury > Captain Marvel",
+		},
+		"the lowest common manager": {
+			path: "/charts/avengers/lowest-common-manager?employee=Scarlet+Witch&employee=%2316",
+			received: func(body map[string]any) string {
+				return body["manager"].(map[string]any)["name"].(string)
+			},
+			wanted: "Black Widow",
+		},
+		"a chain of command": {
+			path: "/charts/avengers/chain?employee=%2320",
+			received: func(body map[string]any) string {
+				employees := make([]any, 0)
+
+				for _, link := range body["chain"].([]any) {
+					employees = append(employees, link.(map[string]any)["employee"])
+				}
+
+				return strings.Join(names(employees), " > ")
+			},
+			wanted: "Hawkeye > Captain Marvel > Nick Fury",
+		},
+		"reports to a depth": {
+			path: "/charts/avengers/reports?employee=Iron+Man&depth=1",
+			received: func(body map[string]any) string {
+				levels := body["levels"].([]any)
+				return strings.Join(names(levels[0].(map[string]any)["employees"]), ", ")
+			},
+			wanted: "Black Widow",
+		},
+		"a search": {
+			path: "/charts/avengers/search?q=hawkeye",
+			received: func(body map[string]any) string {
+				return strings.Join(names(body["employees"]), ", ")
+			},
+			wanted: "Hawkeye, Hawkeye",
+		},
+		"stats": {
+			path: "/charts/avengers/stats",
+			received: func(body map[string]any) string {
+				return jsonValue(body["headcount"]) + " people in " + jsonValue(body["depth"]) + " levels"
+			},
+			wanted: "7 people in 4 levels",
+		},
+	}
+
+	server := setupTestServer(t)
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			status, body := get(t, server, tc.path)
+
+			if status != http.StatusOK {
+				t.Fatalf("The status %d was returned instead of 200, with the body %v", status, body)
+			}
+
+			if received := tc.received(body); received != tc.wanted {
+				t.Errorf("The received output '%s' was not equal to the expected output '%s'", received, tc.wanted)
+			}
+		})
+	}
+}
+
+func jsonValue(value any) string {
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
+
+func TestServingBadRequestsErrors(t *testing.T) {
+	type testCase struct {
+		path           string
+		expectedStatus int
+	}
+
+	testCases := map[string]testCase{
+		"with an unknown chart":                  {path: "/charts/justice-league/stats", expectedStatus: http.StatusNotFound},
+		"with an unknown employee":               {path: "/charts/avengers/chain?employee=Thor", expectedStatus: http.StatusNotFound},
+		"with a missing parameter":               {path: "/charts/avengers/path?from=Hawkeye", expectedStatus: http.StatusBadRequest},
+		"with an ambiguous employee":             {path: "/charts/avengers/chain?employee=Hawkeye", expectedStatus: http.StatusBadRequest},
+		"with an invalid depth":                  {path: "/charts/avengers/reports?employee=Iron+Man&depth=lots", expectedStatus: http.StatusBadRequest},
+		"with one employee for a common manager": {path: "/charts/avengers/lowest-common-manager?employee=Iron+Man", expectedStatus: http.StatusBadRequest},
+		"with a broken chart":                    {path: "/charts/broken/chain?employee=Loop+A", expectedStatus: http.StatusUnprocessableEntity},
+	}
+
+	server := setupTestServer(t)
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			status, body := get(t, server, tc.path)
+
+			if status != tc.expectedStatus {
+				t.Errorf("The status %d was returned, but it was not the expected status %d", status, tc.expectedStatus)
+			}
+
+			if message, _ := body["error"].(string); message == "" {
+				t.Errorf("The error response %v didn't include an error message.", body)
+			}
+		})
+	}
+}
+
+func TestCreatingServersErrors(t *testing.T) {
+	if _, err := NewServer(nil, DefaultTimeout); err != errServerNoCharts {
+		t.Errorf("The error '%v' was returned, but it was not the expected error '%v'", err, errServerNoCharts)
+	}
+
+	if _, err := NewServer([]Chart{avengersChart, avengersChart}, DefaultTimeout); err != errServerDuplicateChart {
+		t.Errorf("The error '%v' was returned, but it was not the expected error '%v'", err, errServerDuplicateChart)
+	}
+}
+
+func TestRequestsThatTakeTooLongTimeOut(t *testing.T) {
+	server, _ := NewServer([]Chart{avengersChart}, time.Millisecond)
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	// Swap the real handler's work for something that never finishes in time.
+	server.handler = http.TimeoutHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { <-release }), time.Millisecond, `{"error":"The request took too long to answer."}`)
+	recorder := httptest.NewRecorder()
+	server.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/charts", nil))
+
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("The status %d was returned, but it was not the expected status %d", recorder.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestPathSearchesStopWhenTheRequestIsCancelled(t *testing.T) {
+	server, _ := NewServer([]Chart{avengersChart}, DefaultTimeout)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := pathHandler(ctx, *server.charts["avengers"].Load(), url.Values{"from": {"Scarlet Witch"}, "to": {"Captain Marvel"}})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("The error '%v' was returned, but it was not the expected error '%v'", err, context.Canceled)
+	}
+
+	recorder := httptest.NewRecorder()
+	writeError(recorder, err)
+
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Errorf("The status %d was returned, but it was not the expected status %d", recorder.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func TestServingStopsWhenTheContextIsCancelled(t *testing.T) {
+	server, _ := NewServer([]Chart{avengersChart}, DefaultTimeout)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+
+	go func() {
+		stopped <- server.Serve(ctx, listener)
+	}()
+
+	address := url.URL{Scheme: "http", Host: listener.Addr().String(), Path: "/charts"}
+	response, err := http.Get(address.String())
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	io.Copy(io.Discard, response.Body)
+	response.Body.Close()
+	cancel()
+
+	select {
+	case err := <-stopped:
+		if err != nil {
+			t.Errorf("An error '%s' was returned when none was expected", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("The server didn't stop after the context was cancelled.")
+	}
+}
+
+func TestReloadingCharts(t *testing.T) {
+	server, _ := NewServer([]Chart{avengersChart}, DefaultTimeout)
+	testServer := httptest.NewServer(server)
+	t.Cleanup(testServer.Close)
+
+	// Maria Hill joins, reporting to Nick Fury.
+	updated := avengersChart
+	updated.Chart = append(slices.Clone(avengersChart.Chart), model.Employee{Id: 7, Name: "Maria Hill", ManagerId: 1})
+
+	if err := server.Reload(updated); err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	if status, _ := get(t, testServer, "/charts/avengers/chain?employee=Maria+Hill"); status != http.StatusOK {
+		t.Errorf("The status %d was returned after reloading, but it was not the expected status %d", status, http.StatusOK)
+	}
+
+	// A version that fails validation leaves the last good one in place.
+	broken := brokenChart
+	broken.Name = "avengers"
+
+	if err := server.Reload(broken); !errors.Is(err, errServerInvalidReload) {
+		t.Errorf("The error '%v' was returned, but it was not the expected error '%v'", err, errServerInvalidReload)
+	}
+
+	if status, _ := get(t, testServer, "/charts/avengers/chain?employee=Maria+Hill"); status != http.StatusOK {
+		t.Errorf("The status %d was returned after a failed reload, but it was not the expected status %d", status, http.StatusOK)
+	}
+
+	if err := server.Reload(brokenChart); !errors.Is(err, errServerUnknownChart) {
+		t.Errorf("The error '%v' was returned, but it was not the expected error '%v'", err, errServerUnknownChart)
+	}
+}
@@ -0,0 +1,98 @@
+// Package timeline answers questions about the chart as it was on a given date,
+// from a series of dated snapshots, effective-dated rows, or both.
+package timeline
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+var (
+	errTimelineNoSnapshots         = errors.New("The timeline doesn't have any snapshots.")
+	errTimelineDuplicateSnapshot   = errors.New("The timeline has more than one snapshot for the same date.")
+	errTimelineBeforeFirstSnapshot = errors.New("The date provided is before the earliest snapshot in the timeline.")
+	errTimelineOverlappingRows     = errors.New("An employee has more than one row in effect on the date provided - check the start and end dates.")
+)
+
+// A copy of the chart as it was on a particular date.
+// A snapshot without a date is treated as having always applied.
+type Snapshot struct {
+	AsOf     time.Time
+	Chart    model.OrganisationChart
+	Metadata model.ChartMetadata
+}
+
+// Snapshots ordered by date, earliest first.
+type Timeline struct {
+	snapshots []Snapshot
+}
+
+func NewTimeline(snapshots ...Snapshot) (Timeline, error) {
+	if len(snapshots) == 0 {
+		return Timeline{}, errTimelineNoSnapshots
+	}
+
+	sorted := slices.Clone(snapshots)
+	slices.SortStableFunc(sorted, func(a, b Snapshot) int { return a.AsOf.Compare(b.AsOf) })
+
+	for i := 1; i < len(sorted); i++ {
+		if sorted[i].AsOf.Equal(sorted[i-1].AsOf) {
+			return Timeline{}, fmt.Errorf("%w Two snapshots are dated %s.", errTimelineDuplicateSnapshot, sorted[i].AsOf.Format(time.DateOnly))
+		}
+	}
+
+	return Timeline{snapshots: sorted}, nil
+}
+
+// The most recent snapshot taken on or before the date.
+func (t Timeline) Snapshot(date time.Time) (Snapshot, error) {
+	i, found := slices.BinarySearchFunc(t.snapshots, date, func(s Snapshot, date time.Time) int { return s.AsOf.Compare(date) })
+
+	// BinarySearchFunc gives the position the date would be inserted at, so the snapshot before it is the one in effect.
+	if !found {
+		i--
+	}
+
+	if i < 0 {
+		return Snapshot{}, fmt.Errorf("%w The earliest snapshot is dated %s.", errTimelineBeforeFirstSnapshot, t.snapshots[0].AsOf.Format(time.DateOnly))
+	}
+
+	return t.snapshots[i], nil
+}
+
+// The chart as it was on the date, ready to hand to the analyser.
+func (t Timeline) AsOf(date time.Time) (model.OrganisationChart, error) {
+	snapshot, err := t.Snapshot(date)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return Materialise(snapshot.Chart, date)
+}
+
+// Keeps the rows in effect on the date. Rows without start or end dates are always in effect,
+// so a chart without effective dates comes back unchanged.
+func Materialise(chart model.OrganisationChart, date time.Time) (model.OrganisationChart, error) {
+	materialised := make(model.OrganisationChart, 0, len(chart))
+	seenIds := make(map[int]bool, len(chart))
+
+	for _, employee := range chart {
+		if !employee.Effective.Contains(date) {
+			continue
+		}
+
+		if seenIds[employee.Id] {
+			return nil, fmt.Errorf("%w Employee %d has two rows in effect on %s.", errTimelineOverlappingRows, employee.Id, date.Format(time.DateOnly))
+		}
+
+		seenIds[employee.Id] = true
+		materialised = append(materialised, employee)
+	}
+
+	return materialised, nil
+}
@@ -0,0 +1,125 @@
+package timeline
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+func date(s string) time.Time {
+	d, _ := time.Parse(time.DateOnly, s)
+	return d
+}
+
+// Adrian moves from Lawrence to Natalie at the start of July, and Joshua leaves at the end of August.
+var effectiveDatedOrgChart = model.OrganisationChart{
+	model.Employee{Id: 1, Name: "Lawrence"},
+	model.Employee{Id: 2, Name: "Adrian", ManagerId: 1, Effective: model.DateRange{To: date("2025-06-30")}},
+	model.Employee{Id: 2, Name: "Adrian", ManagerId: 4, Effective: model.DateRange{From: date("2025-07-01")}},
+	model.Employee{Id: 3, Name: "Joshua", ManagerId: 2, Effective: model.DateRange{From: date("2025-01-01"), To: date("2025-08-31")}},
+	model.Employee{Id: 4, Name: "Natalie", ManagerId: 1},
+}
+
+func ids(chart model.OrganisationChart) [][2]int {
+	result := make([][2]int, 0, len(chart))
+
+	for _, employee := range chart {
+		result = append(result, [2]int{employee.Id, employee.ManagerId})
+	}
+
+	return result
+}
+
+func TestMaterialisingEffectiveDatedRows(t *testing.T) {
+	type testCase struct {
+		date           string
+		expectedResult [][2]int // employee ID, manager ID
+	}
+
+	testCases := map[string]testCase{
+		"before anyone was hired": {date: "2024-12-31", expectedResult: [][2]int{{1, 0}, {2, 1}, {4, 1}}},
+		"on a start date":         {date: "2025-01-01", expectedResult: [][2]int{{1, 0}, {2, 1}, {3, 2}, {4, 1}}},
+		"on the day of a move":    {date: "2025-07-01", expectedResult: [][2]int{{1, 0}, {2, 4}, {3, 2}, {4, 1}}},
+		"on an end date":          {date: "2025-08-31", expectedResult: [][2]int{{1, 0}, {2, 4}, {3, 2}, {4, 1}}},
+		"after someone left":      {date: "2025-09-01", expectedResult: [][2]int{{1, 0}, {2, 4}, {4, 1}}},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			chart, err := Materialise(effectiveDatedOrgChart, date(tc.date))
+
+			if err != nil {
+				t.Fatalf("An error '%s' was returned when none was expected", err)
+			}
+
+			if !reflect.DeepEqual(ids(chart), tc.expectedResult) {
+				t.Errorf("The chart %v was not equal to the expected chart %v", ids(chart), tc.expectedResult)
+			}
+		})
+	}
+}
+
+func TestMaterialisingOverlappingRowsErrors(t *testing.T) {
+	chart := model.OrganisationChart{
+		model.Employee{Id: 1, Name: "Lawrence", Effective: model.DateRange{To: date("2025-06-30")}},
+		model.Employee{Id: 1, Name: "Lawrence", Effective: model.DateRange{From: date("2025-06-01")}},
+	}
+
+	if _, err := Materialise(chart, date("2025-06-15")); !errors.Is(err, errTimelineOverlappingRows) {
+		t.Errorf("The error '%v' was returned, but it was not the expected error '%v'", err, errTimelineOverlappingRows)
+	}
+}
+
+func TestPickingSnapshotsByDate(t *testing.T) {
+	march := Snapshot{AsOf: date("2025-03-01"), Chart: model.OrganisationChart{{Id: 1, Name: "March"}}}
+	april := Snapshot{AsOf: date("2025-04-01"), Chart: model.OrganisationChart{{Id: 1, Name: "April"}}}
+
+	// Given out of order, to check they're sorted.
+	timeline, err := NewTimeline(april, march)
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	type testCase struct {
+		date          string
+		expectedName  string
+		expectedError error
+	}
+
+	testCases := map[string]testCase{
+		"on the day of a snapshot":  {date: "2025-03-01", expectedName: "March"},
+		"between snapshots":         {date: "2025-03-20", expectedName: "March"},
+		"after the latest snapshot": {date: "2026-01-01", expectedName: "April"},
+		"before the first snapshot": {date: "2025-02-28", expectedError: errTimelineBeforeFirstSnapshot},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			chart, err := timeline.AsOf(date(tc.date))
+
+			if !errors.Is(err, tc.expectedError) {
+				t.Fatalf("The error '%v' was returned, but it was not the expected error '%v'", err, tc.expectedError)
+			}
+
+			if err == nil && chart[0].Name != tc.expectedName {
+				t.Errorf("The chart from the '%s' snapshot was used, but the '%s' snapshot was expected", chart[0].Name, tc.expectedName)
+			}
+		})
+	}
+}
+
+func TestCreatingInvalidTimelinesErrors(t *testing.T) {
+	if _, err := NewTimeline(); err != errTimelineNoSnapshots {
+		t.Errorf("The error '%v' was returned, but it was not the expected error '%v'", err, errTimelineNoSnapshots)
+	}
+
+	snapshot := Snapshot{AsOf: date("2025-03-01")}
+
+	if _, err := NewTimeline(snapshot, snapshot); !errors.Is(err, errTimelineDuplicateSnapshot) {
+		t.Errorf("The error '%v' was returned, but it was not the expected error '%v'", err, errTimelineDuplicateSnapshot)
+	}
+}
@@ -0,0 +1,103 @@
+// Package watch notices when chart files change on disk. It polls rather than using OS-specific file notifications,
+// so it works the same everywhere, including on network drives where notifications often don't arrive.
+package watch
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+)
+
+// How often paths are checked, unless told otherwise.
+const DefaultInterval = 2 * time.Second
+
+// Checks each path every interval and calls changed with any path that's different from last time, until the context is cancelled.
+// A path is a file or a directory of snapshots - a directory has changed when any file in it has, or one has been added or removed.
+//
+// A change is only reported once the path has stayed the same for a whole interval, so a file that's still being
+// written isn't picked up half finished.
+func Poll(ctx context.Context, interval time.Duration, paths []string, changed func(path string)) {
+	current := make(map[string]fingerprint, len(paths))
+	pending := make(map[string]fingerprint)
+
+	for _, path := range paths {
+		current[path] = fingerprintOf(path)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for _, path := range paths {
+			latest := fingerprintOf(path)
+
+			if latest == current[path] {
+				delete(pending, path)
+				continue
+			}
+
+			if last, ok := pending[path]; !ok || last != latest {
+				pending[path] = latest
+				continue
+			}
+
+			current[path] = latest
+			delete(pending, path)
+			changed(path)
+		}
+	}
+}
+
+// What a path looked like when it was checked - the size and modification time of the file, or of every file in the directory.
+// Paths that can't be read all look the same, so a file that's missing for a while only counts as a change once it's back.
+type fingerprint string
+
+func fingerprintOf(path string) fingerprint {
+	info, err := os.Stat(path)
+
+	if err != nil {
+		return ""
+	}
+
+	if !info.IsDir() {
+		return fingerprint(describeFile(info))
+	}
+
+	entries, err := os.ReadDir(path)
+
+	if err != nil {
+		return ""
+	}
+
+	var builder strings.Builder
+
+	// The same files the snapshot loader reads - hidden files (e.g. editor swap files) are skipped.
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		info, err := entry.Info()
+
+		if err != nil {
+			continue
+		}
+
+		fmt.Fprintf(&builder, "%s:%s;", entry.Name(), describeFile(info))
+	}
+
+	return fingerprint(builder.String())
+}
+
+func describeFile(info fs.FileInfo) string {
+	return fmt.Sprintf("%d@%d", info.Size(), info.ModTime().UnixNano())
+}
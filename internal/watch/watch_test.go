@@ -0,0 +1,100 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testInterval = 10 * time.Millisecond
+
+// Polls in the background, handing each change reported over a channel.
+func startPolling(t *testing.T, paths ...string) <-chan string {
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := make(chan string, 10)
+	stopped := make(chan struct{})
+
+	go func() {
+		Poll(ctx, testInterval, paths, func(path string) { changes <- path })
+		close(stopped)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		<-stopped
+	})
+
+	return changes
+}
+
+func writeFile(t *testing.T, path string, content string, modified time.Time) {
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	// Set explicitly, as two writes close together can share a modification time on some filesystems.
+	if err := os.Chtimes(path, modified, modified); err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+}
+
+func expectChange(t *testing.T, changes <-chan string, expected string) {
+	select {
+	case path := <-changes:
+		if path != expected {
+			t.Errorf("The change was reported for '%s' rather than the expected path '%s'", path, expected)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("No change was reported for '%s'.", expected)
+	}
+}
+
+func expectNoChange(t *testing.T, changes <-chan string) {
+	select {
+	case path := <-changes:
+		t.Errorf("A change was reported for '%s' when none was expected.", path)
+	case <-time.After(10 * testInterval):
+	}
+}
+
+func TestPollingReportsChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	chart, other := filepath.Join(dir, "avengers.txt"), filepath.Join(dir, "shield.txt")
+	start := time.Now().Add(-time.Hour)
+	writeFile(t, chart, "| ID | Name | Manager ID |", start)
+	writeFile(t, other, "| ID | Name | Manager ID |", start)
+
+	changes := startPolling(t, chart, other)
+	expectNoChange(t, changes)
+
+	writeFile(t, chart, "| ID | Name | Manager ID |\n| 1 | Nick Fury | |", start.Add(time.Minute))
+	expectChange(t, changes, chart)
+	expectNoChange(t, changes)
+}
+
+func TestPollingReportsChangedSnapshotDirectories(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Now().Add(-time.Hour)
+	writeFile(t, filepath.Join(dir, "2026-01-01.txt"), "| ID | Name | Manager ID |", start)
+
+	changes := startPolling(t, dir)
+
+	writeFile(t, filepath.Join(dir, ".2026-02-01.txt.swp"), "swap", start)
+	expectNoChange(t, changes)
+
+	writeFile(t, filepath.Join(dir, "2026-02-01.txt"), "| ID | Name | Manager ID |", start)
+	expectChange(t, changes, dir)
+}
+
+func TestPollingReportsFilesOnceTheyAppear(t *testing.T) {
+	dir := t.TempDir()
+	chart := filepath.Join(dir, "avengers.txt")
+
+	changes := startPolling(t, chart)
+	expectNoChange(t, changes)
+
+	writeFile(t, chart, "| ID | Name | Manager ID |", time.Now())
+	expectChange(t, changes, chart)
+}
@@ -0,0 +1,252 @@
+package orgchart
+
+import (
+	"context"
+	"io"
+
+	"github.com/lsg93/org-chart-parser/internal/analysis"
+)
+
+// Options change how an analyser matches names and searches for paths. Without any, names have to match exactly
+// (ignoring case and accents), every candidate path is returned when names are shared, and only solid lines are followed.
+type Option = analysis.AnalyserOption
+
+// Where the Analyse methods write. Without this they write nowhere, which is fine when only the values are wanted.
+func WithOutput(output io.Writer) Option {
+	return analysis.WithOutput(output)
+}
+
+// When a name can't be found, use the closest fuzzy match instead of erroring - as long as there's only one.
+func WithFuzzyAutoSelect() Option {
+	return analysis.WithFuzzyAutoSelect()
+}
+
+// When a selector matches more than one employee, error with the candidates rather than trying every combination.
+func WithStrictNames() Option {
+	return analysis.WithStrictNames()
+}
+
+// Only write the shortest path, rather than every candidate when names are shared.
+func WithShortestPathOnly() Option {
+	return analysis.WithShortestPathOnly()
+}
+
+// Return every path that shares the shortest length, rather than just the first one found.
+func WithAllShortestPaths() Option {
+	return analysis.WithAllShortestPaths()
+}
+
+// Return up to k of the shortest simple paths.
+func WithKShortestPaths(k int) Option {
+	return analysis.WithKShortestPaths(k)
+}
+
+// Choose which kinds of relationship a path may follow. Chains of command, reports and stats always follow solid lines.
+func WithRelationshipTypes(types ...RelationshipType) Option {
+	return analysis.WithRelationshipTypes(types...)
+}
+
+// Rank paths by the total cost of their hops rather than the number of hops, see ParseCostRules.
+func WithWeightedSearch(rules CostRules) Option {
+	return analysis.WithWeightedSearch(rules)
+}
+
+// Search from both ends at once, which is much faster on very large charts. Weighted and all-shortest searches ignore this.
+func WithBidirectionalSearch() Option {
+	return analysis.WithSearchStrategy(analysis.BidirectionalSearch)
+}
+
+// Paths must not pass through anyone matching these selectors (IDs like "#17", names, or attributes like "department=Ops").
+func WithAvoiding(selectors ...string) Option {
+	return analysis.WithAvoiding(selectors...)
+}
+
+// Paths must pass through someone matching each of these selectors, in the order given.
+// Only the shortest such path is found, so this can't be used with WithAllShortestPaths or WithKShortestPaths - Paths returns ErrInvalidRequest.
+func WithRequiring(selectors ...string) Option {
+	return analysis.WithRequiring(selectors...)
+}
+
+// Hop costs for a weighted search.
+type CostRules = analysis.CostRules
+
+// Every hop costs the same.
+func DefaultCostRules() CostRules {
+	return analysis.DefaultCostRules()
+}
+
+// Reads cost rules, one 'name = cost' rule per line, e.g. "up = 1" or "cross-department = 2".
+func ParseCostRules(input io.Reader) (CostRules, error) {
+	rules, err := analysis.ParseCostRules(input)
+
+	return rules, categoriseAnalysis(err)
+}
+
+// Reads a reorg script, one operation per line, e.g. `move "Black Widow" under "Nick Fury"` or `remove Hawkeye`.
+func ParseReorgScript(input io.Reader) ([]ReorgOperation, error) {
+	operations, err := analysis.ParseReorgScript(input)
+
+	return operations, categoriseAnalysis(err)
+}
+
+// Answers questions about a single chart. Methods that take a name accept any selector - a name, an ID like "#17",
+// or a qualified selector like "name=Hawkeye,department=Ops".
+type Analyser struct {
+	analyser queries
+}
+
+// The analyser type itself isn't exported from the internal package, so it's held through the methods used here.
+type queries interface {
+	Analyse(name1 string, name2 string) error
+	AnalyseChainOfCommand(name string) error
+	AnalyseReports(name string, depth int) error
+	AnalyseStats() error
+	AnalyseDiff(previous Chart, format string) error
+	AnalyseReorg(operations []ReorgOperation) error
+	AnalyseContext(ctx context.Context, name1 string, name2 string) error
+	Paths(name1 string, name2 string) ([]PathResult, error)
+	PathsContext(ctx context.Context, name1 string, name2 string) ([]PathResult, error)
+	Employee(id int) (Employee, bool)
+	Find(selector string) ([]Employee, error)
+	FindOne(selector string) (Employee, error)
+	Search(query string) []Employee
+	SuggestNames(name string) []NameSuggestion
+	ChainOfCommand(id int) ([]ChainLink, error)
+	DirectReports(id int) ([]Employee, error)
+	Reports(id int, depth int) ([]ReportLevel, error)
+	LowestCommonManager(id1 int, id2 int) (Employee, error)
+	Stats() Stats
+	Diff(previous Chart) Diff
+	Reorganise(operations []ReorgOperation) (Chart, error)
+	Validate() error
+}
+
+func NewAnalyser(chart Chart, opts ...Option) *Analyser {
+	return &Analyser{analyser: analysis.NewOrganisationChartAnalyser(io.Discard, chart, opts...)}
+}
+
+// Writes the path between the employees matching the two names, or every candidate path when names are shared.
+func (a *Analyser) Analyse(name1 string, name2 string) error {
+	return categoriseAnalysis(a.analyser.Analyse(name1, name2))
+}
+
+// The same as Analyse, but gives up with the context's error once the context is cancelled or its deadline passes.
+// Nothing is written unless the search finishes.
+func (a *Analyser) AnalyseContext(ctx context.Context, name1 string, name2 string) error {
+	return categoriseAnalysis(a.analyser.AnalyseContext(ctx, name1, name2))
+}
+
+// Writes the chain of command for every employee matching the name.
+func (a *Analyser) AnalyseChainOfCommand(name string) error {
+	return categoriseAnalysis(a.analyser.AnalyseChainOfCommand(name))
+}
+
+// Writes the reports beneath every employee matching the name, grouped by level. A depth of 0 means no limit.
+func (a *Analyser) AnalyseReports(name string, depth int) error {
+	return categoriseAnalysis(a.analyser.AnalyseReports(name, depth))
+}
+
+func (a *Analyser) AnalyseStats() error {
+	return categoriseAnalysis(a.analyser.AnalyseStats())
+}
+
+// Writes the changes since the previous chart, in ReportFormatText or ReportFormatJSON.
+func (a *Analyser) AnalyseDiff(previous Chart, format string) error {
+	return categoriseAnalysis(a.analyser.AnalyseDiff(previous, format))
+}
+
+// Writes what the operations would change - the diff, how the stats move and the new chains of command - without changing the chart.
+func (a *Analyser) AnalyseReorg(operations []ReorgOperation) error {
+	return categoriseAnalysis(a.analyser.AnalyseReorg(operations))
+}
+
+// Every candidate path between the employees matching the two names, shortest (or cheapest) first.
+func (a *Analyser) Paths(name1 string, name2 string) ([]PathResult, error) {
+	paths, err := a.analyser.Paths(name1, name2)
+
+	return paths, categoriseAnalysis(err)
+}
+
+// The same as Paths, but gives up with the context's error once the context is cancelled or its deadline passes.
+// The searches check as they go, so even one over a very large chart stops promptly.
+func (a *Analyser) PathsContext(ctx context.Context, name1 string, name2 string) ([]PathResult, error) {
+	paths, err := a.analyser.PathsContext(ctx, name1, name2)
+
+	return paths, categoriseAnalysis(err)
+}
+
+func (a *Analyser) Employee(id int) (Employee, bool) {
+	return a.analyser.Employee(id)
+}
+
+// Every employee the selector matches.
+func (a *Analyser) Find(selector string) ([]Employee, error) {
+	employees, err := a.analyser.Find(selector)
+
+	return employees, categoriseAnalysis(err)
+}
+
+// The one employee the selector matches - matching more than one is an ErrInvalidRequest.
+func (a *Analyser) FindOne(selector string) (Employee, error) {
+	employee, err := a.analyser.FindOne(selector)
+
+	return employee, categoriseAnalysis(err)
+}
+
+// Whoever the query selects, or the closest names if it doesn't select anyone.
+func (a *Analyser) Search(query string) []Employee {
+	return a.analyser.Search(query)
+}
+
+// The names in the chart most like the one given, closest first.
+func (a *Analyser) SuggestNames(name string) []NameSuggestion {
+	return a.analyser.SuggestNames(name)
+}
+
+// The chain of command from the employee (level 0) up to the top of the chart.
+func (a *Analyser) ChainOfCommand(id int) ([]ChainLink, error) {
+	chain, err := a.analyser.ChainOfCommand(id)
+
+	return chain, categoriseAnalysis(err)
+}
+
+func (a *Analyser) DirectReports(id int) ([]Employee, error) {
+	reports, err := a.analyser.DirectReports(id)
+
+	return reports, categoriseAnalysis(err)
+}
+
+// Everyone beneath the employee, grouped by level. A depth of 0 means no limit.
+func (a *Analyser) Reports(id int, depth int) ([]ReportLevel, error) {
+	levels, err := a.analyser.Reports(id, depth)
+
+	return levels, categoriseAnalysis(err)
+}
+
+// The most junior person both employees report up to. If one manages the other, that's the answer.
+func (a *Analyser) LowestCommonManager(id1 int, id2 int) (Employee, error) {
+	manager, err := a.analyser.LowestCommonManager(id1, id2)
+
+	return manager, categoriseAnalysis(err)
+}
+
+func (a *Analyser) Stats() Stats {
+	return a.analyser.Stats()
+}
+
+// What changed since the previous chart, matching employees up by ID.
+func (a *Analyser) Diff(previous Chart) Diff {
+	return a.analyser.Diff(previous)
+}
+
+// Applies the operations to a copy of the chart. The result always passes Validate.
+func (a *Analyser) Reorganise(operations []ReorgOperation) (Chart, error) {
+	chart, err := a.analyser.Reorganise(operations)
+
+	return chart, categoriseAnalysis(err)
+}
+
+// Checks the chart hangs together - unique IDs, managers that exist and no management cycles - returning every problem found.
+func (a *Analyser) Validate() error {
+	return categoriseAnalysis(a.analyser.Validate())
+}
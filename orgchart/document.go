@@ -0,0 +1,66 @@
+package orgchart
+
+import (
+	"io"
+
+	"github.com/lsg93/org-chart-parser/internal/parser"
+)
+
+// Formats a chart can be written in, see Write.
+const (
+	ChartFormatTable = parser.FormatTable // the same pipe table Parse reads
+	ChartFormatJSON  = parser.FormatJSON
+)
+
+var ChartFormats = []string{ChartFormatTable, ChartFormatJSON}
+
+// Writes the chart out in one of the ChartFormats.
+func Write(output io.Writer, chart Chart, format string) error {
+	writer, err := parser.NewOrganisationChartWriter(output, format)
+
+	if err != nil {
+		return categorise(err, ErrInvalidRequest)
+	}
+
+	return writer.Write(chart)
+}
+
+// A chart file held line by line, so changes can be saved back into it without disturbing anything else -
+// front matter, comments, the rest of a Markdown document, the order of the rows and the widths of the columns all stay as they were.
+type Document struct {
+	document *parser.ChartDocument
+}
+
+func ReadDocument(input io.Reader) (*Document, error) {
+	document, err := parser.ReadChartDocument(input)
+
+	if err != nil {
+		return nil, categorise(err, ErrInvalidChart)
+	}
+
+	return &Document{document: document}, nil
+}
+
+// The chart as it is in the document, ignoring effective dates. Changing it doesn't change the document - use Apply for that.
+func (d *Document) Chart() Chart {
+	return d.document.Chart()
+}
+
+func (d *Document) Metadata() Metadata {
+	return d.document.Metadata()
+}
+
+// Brings the document in line with the chart (e.g. one from Analyser.Reorganise), matching rows up by ID. Only the cells that
+// changed are rewritten, and new employees go on the end of the table. A document that has more than one row for an employee,
+// or is missing a column for a value being saved, is an ErrInvalidChart.
+func (d *Document) Apply(chart Chart) error {
+	return categorise(d.document.Apply(chart), ErrInvalidChart)
+}
+
+func (d *Document) String() string {
+	return d.document.String()
+}
+
+func (d *Document) WriteTo(output io.Writer) (int64, error) {
+	return d.document.WriteTo(output)
+}
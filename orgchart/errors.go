@@ -0,0 +1,59 @@
+package orgchart
+
+import (
+	"errors"
+
+	"github.com/lsg93/org-chart-parser/internal/analysis"
+)
+
+// Every error from this package matches one of these with errors.Is, while keeping its own message to explain exactly what went wrong.
+// Errors reading files from disk are passed through as they are, so they still match fs.ErrNotExist and friends,
+// and so are the errors from a cancelled context, which match context.Canceled or context.DeadlineExceeded.
+var (
+	// The chart couldn't be read - a malformed table, a bad date, or snapshots that don't fit together.
+	ErrInvalidChart = errors.New("The chart could not be read.")
+	// Something that was asked for isn't in the chart - a name, selector or ID, a path or manager linking two people, or a snapshot for a date.
+	ErrNotFound = errors.New("What was asked for could not be found in the chart.")
+	// The question doesn't make sense as asked, e.g. a name that matches more than one person, a negative depth or a bad line in a script.
+	ErrInvalidRequest = errors.New("The request is not valid.")
+	// The chart is broken in a way that stops the question being answered, e.g. a management cycle.
+	ErrBrokenChart = errors.New("The chart is broken in a way that stops the question being answered.")
+)
+
+// An error that reads exactly as the original, but also matches the kind of problem it is.
+type categorisedError struct {
+	err      error
+	category error
+}
+
+func (e *categorisedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *categorisedError) Unwrap() []error {
+	return []error{e.err, e.category}
+}
+
+func categorise(err error, category error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &categorisedError{err: err, category: category}
+}
+
+// Sorts errors from the analyser into the exported kinds. Anything that doesn't fit (e.g. a failed write) goes through untouched.
+func categoriseAnalysis(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case analysis.IsNotFound(err):
+		return categorise(err, ErrNotFound)
+	case analysis.IsInvalidRequest(err):
+		return categorise(err, ErrInvalidRequest)
+	case analysis.IsBrokenChart(err):
+		return categorise(err, ErrBrokenChart)
+	}
+
+	return err
+}
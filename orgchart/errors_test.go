@@ -0,0 +1,140 @@
+package orgchart_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lsg93/org-chart-parser/orgchart"
+)
+
+func TestErrorsMatchTheirCategory(t *testing.T) {
+	cycle := "| ID | Name | Manager ID |\n| 1 | Nick Fury | 2 |\n| 2 | Captain Marvel | 1 |\n"
+
+	type testCase struct {
+		run           func() error
+		expectedError error
+	}
+
+	analyse := func(chart string, query func(analyser *orgchart.Analyser) error) func() error {
+		return func() error {
+			parsed, _, err := orgchart.Parse(strings.NewReader(chart))
+
+			if err != nil {
+				return err
+			}
+
+			return query(orgchart.NewAnalyser(parsed))
+		}
+	}
+
+	testCases := map[string]testCase{
+		"with a malformed chart": {
+			run: func() error {
+				_, _, err := orgchart.Parse(strings.NewReader("| ID | Name |\n| 1 | Nick Fury |\n"))
+				return err
+			},
+			expectedError: orgchart.ErrInvalidChart,
+		},
+		"with a name that isn't in the chart": {
+			run: analyse(avengers, func(analyser *orgchart.Analyser) error {
+				_, err := analyser.Paths("Hawkeye", "Thanos")
+				return err
+			}),
+			expectedError: orgchart.ErrNotFound,
+		},
+		"with a negative depth": {
+			run: analyse(avengers, func(analyser *orgchart.Analyser) error {
+				_, err := analyser.Reports(1, -1)
+				return err
+			}),
+			expectedError: orgchart.ErrInvalidRequest,
+		},
+		"with a management cycle": {
+			run: analyse(cycle, func(analyser *orgchart.Analyser) error {
+				_, err := analyser.ChainOfCommand(1)
+				return err
+			}),
+			expectedError: orgchart.ErrBrokenChart,
+		},
+		"with a required employee and k shortest paths": {
+			run: func() error {
+				chart, _, err := orgchart.Parse(strings.NewReader(avengers))
+
+				if err != nil {
+					return err
+				}
+
+				_, err = orgchart.NewAnalyser(chart, orgchart.WithRequiring("Nick Fury"), orgchart.WithKShortestPaths(2)).Paths("Hawkeye", "Iron Man")
+				return err
+			},
+			expectedError: orgchart.ErrInvalidRequest,
+		},
+		"with a bad line in a reorg script": {
+			run: func() error {
+				_, err := orgchart.ParseReorgScript(strings.NewReader("promote Hawkeye"))
+				return err
+			},
+			expectedError: orgchart.ErrInvalidRequest,
+		},
+		"with an unknown chart format": {
+			run: func() error {
+				return orgchart.Write(&strings.Builder{}, nil, "yaml")
+			},
+			expectedError: orgchart.ErrInvalidRequest,
+		},
+		"with a cancelled parse": {
+			run: func() error {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				_, _, err := orgchart.ParseContext(ctx, strings.NewReader(avengers))
+				return err
+			},
+			expectedError: context.Canceled,
+		},
+		"with a search past its deadline": {
+			run: analyse(avengers, func(analyser *orgchart.Analyser) error {
+				ctx, cancel := context.WithDeadline(context.Background(), time.Now())
+				defer cancel()
+
+				_, err := analyser.PathsContext(ctx, "Hawkeye", "Iron Man")
+				return err
+			}),
+			expectedError: context.DeadlineExceeded,
+		},
+		"with a chart file that isn't there": {
+			run: func() error {
+				_, err := orgchart.Load(filepath.Join(t.TempDir(), "missing.txt"))
+				return err
+			},
+			expectedError: fs.ErrNotExist,
+		},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			if err := tc.run(); !errors.Is(err, tc.expectedError) {
+				t.Errorf("The error '%v' was returned, but it was not the expected error '%v'", err, tc.expectedError)
+			}
+		})
+	}
+}
+
+func TestCategorisedErrorsKeepTheirMessage(t *testing.T) {
+	chart, _, err := orgchart.Parse(strings.NewReader(avengers))
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	_, err = orgchart.NewAnalyser(chart).FindOne("Thanos")
+
+	if err == nil || err.Error() == orgchart.ErrNotFound.Error() || !strings.Contains(err.Error(), "Thanos") {
+		t.Errorf("The error '%v' should have explained which name couldn't be found.", err)
+	}
+}
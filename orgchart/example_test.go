@@ -0,0 +1,195 @@
+package orgchart_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lsg93/org-chart-parser/orgchart"
+)
+
+const avengers = `| ID | Name           | Manager ID |
+| 1  | Nick Fury      |            |
+| 2  | Captain Marvel | 1          |
+| 3  | Black Widow    | 1          |
+| 4  | Hawkeye        | 3          |
+| 5  | Iron Man       | 2          |
+`
+
+func ExampleParse() {
+	chart, _, err := orgchart.Parse(strings.NewReader(avengers))
+
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(len(chart), chart[3].Name)
+	// Output: 5 Hawkeye
+}
+
+func ExampleParse_asOf() {
+	joiners := "| ID | Name | Manager ID | Start Date |\n| 1 | Nick Fury | | |\n| 2 | Hawkeye | 1 | 2025-06-01 |\n"
+	chart, _, err := orgchart.Parse(strings.NewReader(joiners), orgchart.AsOf(time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)))
+
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(len(chart))
+	// Output: 1
+}
+
+func ExampleGuessVacancies() {
+	labelled := "| ID | Name | Manager ID |\n| 1 | Nick Fury | |\n| 2 | TBH - Agent | 1 |\n"
+	chart, _, err := orgchart.Parse(strings.NewReader(labelled), orgchart.GuessVacancies())
+
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(chart[0].Vacant, chart[1].Vacant)
+	// Output: false true
+}
+
+func ExampleAnalyser_Paths() {
+	chart, _, err := orgchart.Parse(strings.NewReader(avengers))
+
+	if err != nil {
+		panic(err)
+	}
+
+	paths, err := orgchart.NewAnalyser(chart).Paths("Hawkeye", "Iron Man")
+
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(paths[0].Ids, paths[0].Cost)
+	// Output: [4 3 1 2 5] 4
+}
+
+func ExampleAnalyser_PathsContext() {
+	chart, _, err := orgchart.Parse(strings.NewReader(avengers))
+
+	if err != nil {
+		panic(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	paths, err := orgchart.NewAnalyser(chart).PathsContext(ctx, "Hawkeye", "Iron Man")
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		fmt.Println("The search took too long.")
+		return
+	}
+
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(paths[0].Ids)
+	// Output: [4 3 1 2 5]
+}
+
+func ExampleAnalyser_Analyse() {
+	chart, _, err := orgchart.Parse(strings.NewReader(avengers))
+
+	if err != nil {
+		panic(err)
+	}
+
+	if err := orgchart.NewAnalyser(chart, orgchart.WithOutput(os.Stdout)).Analyse("Hawkeye", "Captain Marvel"); err != nil {
+		panic(err)
+	}
+	// Output: Hawkeye (4) -> Black Widow (3) -> Nick Fury (1) <- Captain Marvel (2)
+}
+
+func ExampleAnalyser_ChainOfCommand() {
+	chart, _, err := orgchart.Parse(strings.NewReader(avengers))
+
+	if err != nil {
+		panic(err)
+	}
+
+	chain, err := orgchart.NewAnalyser(chart).ChainOfCommand(4)
+
+	if err != nil {
+		panic(err)
+	}
+
+	for _, link := range chain {
+		fmt.Println(link.Level, link.Employee.Name)
+	}
+	// Output:
+	// 0 Hawkeye
+	// 1 Black Widow
+	// 2 Nick Fury
+}
+
+func ExampleAnalyser_LowestCommonManager() {
+	chart, _, err := orgchart.Parse(strings.NewReader(avengers))
+
+	if err != nil {
+		panic(err)
+	}
+
+	manager, err := orgchart.NewAnalyser(chart).LowestCommonManager(4, 5)
+
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(manager.Name)
+	// Output: Nick Fury
+}
+
+func ExampleAnalyser_Reorganise() {
+	document, err := orgchart.ReadDocument(strings.NewReader(avengers))
+
+	if err != nil {
+		panic(err)
+	}
+
+	operations, err := orgchart.ParseReorgScript(strings.NewReader(`move Hawkeye under "Captain Marvel"`))
+
+	if err != nil {
+		panic(err)
+	}
+
+	chart, err := orgchart.NewAnalyser(document.Chart()).Reorganise(operations)
+
+	if err != nil {
+		panic(err)
+	}
+
+	if err := document.Apply(chart); err != nil {
+		panic(err)
+	}
+
+	fmt.Print(document)
+	// Output:
+	// | ID | Name           | Manager ID |
+	// | 1  | Nick Fury      |            |
+	// | 2  | Captain Marvel | 1          |
+	// | 3  | Black Widow    | 1          |
+	// | 4  | Hawkeye        | 2          |
+	// | 5  | Iron Man       | 2          |
+}
+
+func ExampleErrNotFound() {
+	chart, _, err := orgchart.Parse(strings.NewReader(avengers))
+
+	if err != nil {
+		panic(err)
+	}
+
+	_, err = orgchart.NewAnalyser(chart).Paths("Hawkeye", "Thanos")
+
+	fmt.Println(errors.Is(err, orgchart.ErrNotFound))
+	// Output: true
+}
@@ -0,0 +1,322 @@
+package orgchart
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/lsg93/org-chart-parser/internal/analysis"
+	"github.com/lsg93/org-chart-parser/internal/index"
+	"github.com/lsg93/org-chart-parser/internal/model"
+	"github.com/lsg93/org-chart-parser/internal/parser"
+	"github.com/lsg93/org-chart-parser/internal/timeline"
+)
+
+var (
+	errOrgChartUndatedSnapshot = errors.New("Every chart in a snapshot directory needs a date, either as 'as of' in its front matter or in its file name (e.g. 2025-03-01.txt).")
+	errOrgChartUnreadableFile  = errors.New("There was an error reading the file.")
+)
+
+// Options for reading charts.
+type ParseOption func(*parseConfig)
+
+type parseConfig struct {
+	asOf           time.Time // zero means today
+	guessVacancies bool
+}
+
+// Read the chart as it was on the date - from effective-dated rows, and when loading a directory, from the snapshot in effect.
+// Without this, charts are read as they are today.
+func AsOf(date time.Time) ParseOption {
+	return func(c *parseConfig) {
+		c.asOf = date
+	}
+}
+
+// Treat positions named like an open role (see IsVacancyName) as vacant, as well as those the Vacant column says are.
+// Without this, only the Vacant column counts, as real names can look like labels too.
+func GuessVacancies() ParseOption {
+	return func(c *parseConfig) {
+		c.guessVacancies = true
+	}
+}
+
+func newParseConfig(opts []ParseOption) parseConfig {
+	var config parseConfig
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return config
+}
+
+// Dates in charts are all midnight UTC, so today has to be too for end dates to include the whole day.
+func (c parseConfig) date() time.Time {
+	if !c.asOf.IsZero() {
+		return c.asOf
+	}
+
+	now := time.Now()
+
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Whatever's guessed goes on a copy, so charts held elsewhere (e.g. in an index) aren't changed.
+func (c parseConfig) vacancies(chart Chart) Chart {
+	if !c.guessVacancies {
+		return chart
+	}
+
+	return model.GuessVacancies(chart)
+}
+
+// Reads a chart, along with any front matter at the top of it.
+func Parse(input io.Reader, opts ...ParseOption) (Chart, Metadata, error) {
+	return ParseContext(context.Background(), input, opts...)
+}
+
+// The same as Parse, but stops reading with the context's error once the context is cancelled or its deadline passes.
+func ParseContext(ctx context.Context, input io.Reader, opts ...ParseOption) (Chart, Metadata, error) {
+	config := newParseConfig(opts)
+	chart, metadata, err := parseChart(ctx, input)
+
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+
+	chart, err = timeline.Materialise(chart, config.date())
+
+	if err != nil {
+		return nil, Metadata{}, categorise(err, ErrInvalidChart)
+	}
+
+	return config.vacancies(chart), metadata, nil
+}
+
+// Stopping early isn't a problem with the chart, so the context's error comes back as it is.
+func parseChart(ctx context.Context, input io.Reader) (Chart, Metadata, error) {
+	chartParser, err := parser.NewOrganisationChartParser(input)
+
+	if err != nil {
+		return nil, Metadata{}, categorise(err, ErrInvalidChart)
+	}
+
+	chart, err := chartParser.ParseContext(ctx)
+
+	if err != nil && ctx.Err() != nil {
+		return nil, Metadata{}, err
+	}
+
+	if err != nil {
+		return nil, Metadata{}, categorise(err, ErrInvalidChart)
+	}
+
+	return chart, chartParser.Metadata(), nil
+}
+
+// A chart read by Load, with the front matter from its file.
+type LoadedChart struct {
+	Chart    Chart
+	Metadata Metadata
+	tables   *analysis.Tables // from an up to date index, when the rows in Chart are all the rows in it
+}
+
+// Options for an analyser of the chart - the tables from its index, if it had an up to date one, or none.
+func (c LoadedChart) Options() []Option {
+	if c.tables == nil {
+		return nil
+	}
+
+	return []Option{analysis.WithTables(*c.tables)}
+}
+
+// An analyser for the chart, which uses the tables from its index if it had an up to date one.
+func (c LoadedChart) Analyser(opts ...Option) *Analyser {
+	return NewAnalyser(c.Chart, append(c.Options(), opts...)...)
+}
+
+// Reads the chart at the path, which can be a single file or a directory of dated snapshots.
+// A file with an up to date index next to it (see WriteIndex) is read from the index, which is much quicker for large charts.
+func Load(path string, opts ...ParseOption) (LoadedChart, error) {
+	return LoadContext(context.Background(), path, opts...)
+}
+
+// The same as Load, but stops with the context's error once the context is cancelled or its deadline passes.
+func LoadContext(ctx context.Context, path string, opts ...ParseOption) (LoadedChart, error) {
+	config := newParseConfig(opts)
+	chartTimeline, tables, err := loadTimeline(ctx, path)
+
+	if err != nil {
+		return LoadedChart{}, err
+	}
+
+	date := config.date()
+	snapshot, err := chartTimeline.Snapshot(date)
+
+	if err != nil {
+		return LoadedChart{}, categorise(err, ErrNotFound)
+	}
+
+	chart, err := timeline.Materialise(snapshot.Chart, date)
+
+	if err != nil {
+		return LoadedChart{}, categorise(err, ErrInvalidChart)
+	}
+
+	loaded := LoadedChart{Chart: config.vacancies(chart), Metadata: snapshot.Metadata}
+
+	// The metadata should show the date that was asked for, rather than when the snapshot was taken.
+	if !config.asOf.IsZero() {
+		loaded.Metadata.AsOf = config.asOf
+	}
+
+	// The tables were built from every row in the file, so they only fit if none were left out for the date.
+	if len(chart) == len(snapshot.Chart) {
+		loaded.tables = tables
+	}
+
+	return loaded, nil
+}
+
+// A single file is one undated snapshot - any effective dates in it still apply. It comes with its tables if it has an up to date index.
+// In a directory every file is a snapshot, dated by its front matter or its file name.
+func loadTimeline(ctx context.Context, path string) (timeline.Timeline, *analysis.Tables, error) {
+	info, err := os.Stat(path)
+
+	if err != nil {
+		return timeline.Timeline{}, nil, err
+	}
+
+	if !info.IsDir() {
+		chart, metadata, tables, err := loadFile(ctx, path)
+
+		if err != nil {
+			return timeline.Timeline{}, nil, err
+		}
+
+		chartTimeline, err := timeline.NewTimeline(timeline.Snapshot{Chart: chart, Metadata: metadata})
+
+		return chartTimeline, tables, categorise(err, ErrInvalidChart)
+	}
+
+	entries, err := os.ReadDir(path)
+
+	if err != nil {
+		return timeline.Timeline{}, nil, errOrgChartUnreadableFile
+	}
+
+	snapshots := make([]timeline.Snapshot, 0, len(entries))
+
+	// Snapshots can be indexed too, but their tables aren't used, so there's only a faster parse to gain.
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || filepath.Ext(entry.Name()) == index.Extension {
+			continue
+		}
+
+		chart, metadata, _, err := loadFile(ctx, filepath.Join(path, entry.Name()))
+
+		if err != nil {
+			return timeline.Timeline{}, nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+
+		if metadata.AsOf.IsZero() {
+			date, err := time.Parse(time.DateOnly, snapshotDatePattern.FindString(entry.Name()))
+
+			if err != nil {
+				return timeline.Timeline{}, nil, categorise(fmt.Errorf("%w '%s' doesn't have one.", errOrgChartUndatedSnapshot, entry.Name()), ErrInvalidChart)
+			}
+
+			metadata.AsOf = date
+		}
+
+		snapshots = append(snapshots, timeline.Snapshot{AsOf: metadata.AsOf, Chart: chart, Metadata: metadata})
+	}
+
+	chartTimeline, err := timeline.NewTimeline(snapshots...)
+
+	return chartTimeline, nil, categorise(err, ErrInvalidChart)
+}
+
+var snapshotDatePattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}`)
+
+// A chart with an up to date index next to it is read from the index, which skips parsing it.
+func loadFile(ctx context.Context, path string) (Chart, Metadata, *analysis.Tables, error) {
+	data, err := readFile(path)
+
+	if err != nil {
+		return nil, Metadata{}, nil, err
+	}
+
+	// Reading an index can't be stopped part way, but it's quick enough that checking before is plenty.
+	if err := ctx.Err(); err != nil {
+		return nil, Metadata{}, nil, err
+	}
+
+	if chartIndex, ok := readIndex(path, data); ok {
+		return chartIndex.Chart, chartIndex.Metadata, &chartIndex.Tables, nil
+	}
+
+	chart, metadata, err := parseChart(ctx, bytes.NewReader(data))
+
+	return chart, metadata, nil, err
+}
+
+// A file that isn't there keeps its error from os, so it matches fs.ErrNotExist.
+func readFile(path string) ([]byte, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, errOrgChartUnreadableFile
+	}
+
+	return data, nil
+}
+
+// An index that's missing, out of date or can't be read is ignored, and the chart is parsed as though it wasn't there.
+func readIndex(path string, source []byte) (index.Index, bool) {
+	file, err := os.Open(IndexPath(path))
+
+	if err != nil {
+		return index.Index{}, false
+	}
+
+	defer file.Close()
+
+	chartIndex, err := index.ReadFor(bufio.NewReader(file), source)
+
+	return chartIndex, err == nil
+}
+
+// Where the index for a chart file goes, e.g. avengers.txt.idx.
+func IndexPath(path string) string {
+	return path + index.Extension
+}
+
+// Builds an index from the contents of a chart file and writes it out, returning the chart it was built from.
+// Save it at IndexPath for Load to find it - it's used for as long as the chart file stays exactly the same.
+func WriteIndex(output io.Writer, source []byte) (Chart, error) {
+	chartIndex, err := index.Build(source)
+
+	if err != nil {
+		return nil, categorise(err, ErrInvalidChart)
+	}
+
+	if _, err := chartIndex.WriteTo(output); err != nil {
+		return nil, err
+	}
+
+	return chartIndex.Chart, nil
+}
@@ -0,0 +1,103 @@
+package orgchart
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadingChartsAsOfADate(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		// Dated by its file name.
+		"2025-01-01.txt": "| ID | Name | Manager ID |\n| 1 | Lawrence | |\n| 2 | Adrian | 1 |",
+		// Dated by its front matter, with Joshua only joining part way through the snapshot.
+		"march.txt": "---\nname: Avengers Initiative\nas of: 2025-03-01\n---\n| ID | Name | Manager ID | Start Date |\n| 1 | Lawrence | | |\n| 2 | Adrian | 1 | |\n| 3 | Joshua | 2 | 2025-03-15 |",
+	}
+
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+			t.Fatalf("An error '%s' occurred writing the test snapshots", err)
+		}
+	}
+
+	type testCase struct {
+		date              time.Time
+		expectedHeadcount int
+		expectedAsOf      time.Time
+	}
+
+	testCases := map[string]testCase{
+		"from the first snapshot":          {date: time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC), expectedHeadcount: 2, expectedAsOf: time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		"from the second snapshot":         {date: time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC), expectedHeadcount: 2, expectedAsOf: time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)},
+		"after a start date in a snapshot": {date: time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC), expectedHeadcount: 3, expectedAsOf: time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC)},
+		"without a date":                   {expectedHeadcount: 3, expectedAsOf: time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for desc, tc := range testCases {
+		t.Run(desc, func(t *testing.T) {
+			loaded, err := Load(dir, AsOf(tc.date))
+
+			if err != nil {
+				t.Fatalf("An error '%s' was returned when none was expected", err)
+			}
+
+			if len(loaded.Chart) != tc.expectedHeadcount {
+				t.Errorf("The chart had %d employees, expected %d.", len(loaded.Chart), tc.expectedHeadcount)
+			}
+
+			if !loaded.Metadata.AsOf.Equal(tc.expectedAsOf) {
+				t.Errorf("The chart was as of %s, expected %s.", loaded.Metadata.AsOf, tc.expectedAsOf)
+			}
+		})
+	}
+}
+
+func TestLoadingUndatedSnapshotsErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := os.WriteFile(filepath.Join(dir, "latest.txt"), []byte("| ID | Name | Manager ID |\n| 1 | Lawrence | |"), 0o644); err != nil {
+		t.Fatalf("An error '%s' occurred writing the test snapshot", err)
+	}
+
+	if _, err := Load(dir); !errors.Is(err, errOrgChartUndatedSnapshot) || !errors.Is(err, ErrInvalidChart) {
+		t.Errorf("The error '%v' was returned, but it was not the expected error '%v'", err, errOrgChartUndatedSnapshot)
+	}
+}
+
+func TestLoadingChartsWithAnIndex(t *testing.T) {
+	source := []byte("| ID | Name | Manager ID |\n| 1 | Lawrence | |\n| 2 | Adrian | 1 |\n")
+	path := filepath.Join(t.TempDir(), "chart.txt")
+
+	if err := os.WriteFile(path, source, 0o644); err != nil {
+		t.Fatalf("An error '%s' occurred writing the test chart", err)
+	}
+
+	file, err := os.Create(IndexPath(path))
+
+	if err != nil {
+		t.Fatalf("An error '%s' occurred creating the test index", err)
+	}
+
+	if _, err := WriteIndex(file, source); err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	file.Close()
+
+	loaded, err := Load(path)
+
+	if err != nil {
+		t.Fatalf("An error '%s' was returned when none was expected", err)
+	}
+
+	if len(loaded.Chart) != 2 || len(loaded.Options()) != 1 {
+		t.Errorf("The chart had %d employees and %d analyser options, expected 2 and 1.", len(loaded.Chart), len(loaded.Options()))
+	}
+
+	if manager, err := loaded.Analyser().LowestCommonManager(1, 2); err != nil || manager.Id != 1 {
+		t.Errorf("The lowest common manager was %+v (%v), expected Lawrence.", manager, err)
+	}
+}
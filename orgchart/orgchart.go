@@ -0,0 +1,79 @@
+// Package orgchart reads organisation charts and answers questions about them - the path between two people,
+// chains of command, reports, stats, diffs between snapshots and what-if reorganisations.
+//
+// A chart is a pipe separated table with an ID, a name and a manager ID for each person, plus any optional columns
+// (title, department, dotted and interim lines, start and end dates, vacant):
+//
+//	| ID | Name           | Manager ID |
+//	| 1  | Nick Fury      |            |
+//	| 2  | Captain Marvel | 1          |
+//
+// Read one with Parse or Load, then hand it to NewAnalyser. Analysers don't change once they're built, so one can be
+// shared between goroutines.
+package orgchart
+
+import (
+	"github.com/lsg93/org-chart-parser/internal/analysis"
+	"github.com/lsg93/org-chart-parser/internal/model"
+)
+
+// The chart itself. These are the same types the CLI uses, so values can be passed between the two freely.
+type (
+	Chart            = model.OrganisationChart
+	Employee         = model.Employee
+	Relationship     = model.Relationship
+	RelationshipType = model.RelationshipType
+	DateRange        = model.DateRange
+	Metadata         = model.ChartMetadata
+)
+
+const (
+	SolidLine  = model.SolidLine
+	DottedLine = model.DottedLine
+	Interim    = model.Interim
+)
+
+// Every kind of relationship, in the order they're usually listed.
+var RelationshipTypes = model.RelationshipTypes
+
+// What the analyser gives back.
+type (
+	PathResult     = analysis.PathResult
+	ChainLink      = analysis.ChainLink
+	ReportLevel    = analysis.ReportLevel
+	Stats          = analysis.ChartStats
+	SpanOfControl  = analysis.SpanOfControl
+	SpanOutlier    = analysis.SpanOutlier
+	Vacancy        = analysis.Vacancy
+	Diff           = analysis.ChartDiff
+	Rename         = analysis.Rename
+	Move           = analysis.Move
+	ManagerChanges = analysis.ManagerChanges
+	NameSuggestion = analysis.NameSuggestion
+)
+
+// Reorganisations, see Analyser.Reorganise and ParseReorgScript.
+type (
+	ReorgOperation     = analysis.ReorgOperation
+	ReorgOperationKind = analysis.ReorgOperationKind
+)
+
+const (
+	MoveEmployee   = analysis.MoveEmployee
+	RemoveEmployee = analysis.RemoveEmployee
+	RenameEmployee = analysis.RenameEmployee
+	AddEmployee    = analysis.AddEmployee
+	AddVacancy     = analysis.AddVacancy
+)
+
+// Formats the analysis can be written in, where there's a choice (e.g. Analyser.AnalyseDiff).
+const (
+	ReportFormatText = analysis.FormatText
+	ReportFormatJSON = analysis.FormatJSON
+)
+
+// Whether a name is one of the usual labels for an open role, e.g. "TBH" or "Vacancy (Head of Ops)".
+// Charts are only read this way with GuessVacancies.
+func IsVacancyName(name string) bool {
+	return model.IsVacancyName(name)
+}